@@ -0,0 +1,160 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"sync"
+	"time"
+)
+
+type (
+	// BreakerOptions configures the circuit breaker set via
+	// WithCircuitBreaker.
+	BreakerOptions struct {
+		// Threshold is the number of consecutive failures
+		// after which the breaker opens.
+		Threshold int
+		// Cooldown is the time to wait once the breaker has
+		// opened before probing Redis with Ping. If the probe
+		// succeeds the breaker closes, otherwise it waits for
+		// another cooldown.
+		Cooldown time.Duration
+		// FailOpen determines if Get should return
+		// ErrNotFound rather than ErrUnavailable whilst the
+		// breaker is open, so callers treat it as a miss.
+		FailOpen bool
+	}
+	// breaker is a circuit breaker that stops operations
+	// being sent to Redis after consecutive failures.
+	breaker struct {
+		mtx      sync.Mutex
+		opts     BreakerOptions
+		failures int
+		open     bool
+		openedAt time.Time
+		now      func() time.Time
+	}
+)
+
+// WithCircuitBreaker enables the circuit breaker. Once open,
+// reads such as Get return ErrUnavailable (or ErrNotFound
+// when FailOpen is set) immediately and writes such as Set,
+// Delete, Invalidate and Flush become no-ops, until a Ping
+// succeeds. Counters, Update, Add and Replace return
+// ErrUnavailable, as their result cannot be known without
+// Redis.
+func WithCircuitBreaker(opts BreakerOptions) Option {
+	return func(c *config) {
+		if opts.Threshold <= 0 {
+			c.errs = append(c.errs, errors.New("redigo: breaker threshold must be positive"))
+			return
+		}
+		if opts.Cooldown <= 0 {
+			c.errs = append(c.errs, errors.New("redigo: breaker cooldown must be positive"))
+			return
+		}
+		c.breaker = &breaker{opts: opts, now: time.Now}
+	}
+}
+
+// allow reports whether an operation should be sent to
+// Redis. When open and the cooldown has elapsed, Redis is
+// probed using ping to determine if the breaker can close.
+func (b *breaker) allow(ctx context.Context, ping func(ctx context.Context) error) bool {
+	if b == nil {
+		return true
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if !b.open {
+		return true
+	}
+
+	if b.now().Sub(b.openedAt) < b.opts.Cooldown {
+		return false
+	}
+
+	if err := ping(ctx); err != nil {
+		b.openedAt = b.now()
+		return false
+	}
+
+	b.open = false
+	b.failures = 0
+
+	return true
+}
+
+// record records the outcome of an operation, opening
+// the breaker if the threshold has been reached. It
+// reports whether the breaker was opened.
+func (b *breaker) record(err error) bool {
+	if b == nil {
+		return false
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if !isFailure(err) {
+		b.failures = 0
+		return false
+	}
+
+	b.failures++
+	if b.open || b.failures < b.opts.Threshold {
+		return false
+	}
+
+	b.open = true
+	b.openedAt = b.now()
+
+	return true
+}
+
+// unavailable returns the error for the operation when
+// the breaker is open, writes become no-ops except for
+// counters, updates and conditional writes, which return
+// a result.
+func (b *breaker) unavailable(op Operation) error {
+	switch op {
+	case OpIncr, OpUpdate, OpAdd, OpReplace:
+		return ErrUnavailable
+	}
+	if !op.isRead() {
+		return nil
+	}
+	if b.opts.FailOpen {
+		return ErrNotFound
+	}
+	return ErrUnavailable
+}
+
+// isFailure reports whether the error indicates that Redis
+// could not be reached, as opposed to misses, encoding
+// errors, cancellation, error replies from the server or
+// errors from the io.Reader or io.Writer of a stream.
+func isFailure(err error) bool {
+	if err == nil ||
+		errors.Is(err, ErrNotFound) ||
+		errors.Is(err, ErrEncode) ||
+		errors.Is(err, ErrDecode) ||
+		errors.Is(err, ErrKeyTooLong) ||
+		errors.Is(err, ErrConflict) ||
+		errors.Is(err, ErrValueTooLarge) ||
+		errors.Is(err, context.Canceled) {
+		return false
+	}
+	var (
+		rerr  redis.Error
+		ioerr ioError
+	)
+	return !errors.As(err, &rerr) && !errors.As(err, &ioerr)
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ainsleyclark/redigo/mocks"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"testing"
+	"time"
+)
+
+func (t *CacheTestSuite) TestCircuitBreaker() {
+	now := time.Now()
+	failing := true
+
+	c := t.Setup(func(m *mocks.RedisStore, enc *mocks.Encoder) {
+		m.On("Get", mock.Anything, key).
+			Return(func(context.Context, string) *redis.StringCmd {
+				if failing {
+					return redis.NewStringResult("", io.EOF)
+				}
+				return redis.NewStringResult("", redis.Nil)
+			})
+		m.On("Ping", mock.Anything).
+			Return(func(context.Context) *redis.StatusCmd {
+				cmd := redis.NewStatusCmd(ctx)
+				if failing {
+					cmd.SetErr(io.EOF)
+				}
+				return cmd
+			})
+	})
+	c.cfg.breaker = &breaker{
+		opts: BreakerOptions{Threshold: 2, Cooldown: time.Second},
+		now:  func() time.Time { return now },
+	}
+	client := c.client.(*mocks.RedisStore)
+
+	// Closed, failures are returned from Redis.
+	t.ErrorIs(c.Get(ctx, key, &testCacheStruct{}), io.EOF)
+	t.ErrorIs(c.Get(ctx, key, &testCacheStruct{}), io.EOF)
+	client.AssertNumberOfCalls(t.T(), "Get", 2)
+
+	// Open, Redis is not called.
+	t.ErrorIs(c.Get(ctx, key, &testCacheStruct{}), ErrUnavailable)
+	t.NoError(c.Set(ctx, key, value, options))
+	t.NoError(c.Delete(ctx, key))
+	_, err := c.Incr(ctx, key, options)
+	t.ErrorIs(err, ErrUnavailable)
+	err = c.Update(ctx, key, &testCacheStruct{}, func(current any) (any, Options, error) {
+		t.Fail("update func called while the breaker is open")
+		return nil, Options{}, nil
+	})
+	t.ErrorIs(err, ErrUnavailable)
+	client.AssertNumberOfCalls(t.T(), "Get", 2)
+
+	// Cooldown elapsed, probe fails.
+	now = now.Add(time.Second)
+	t.ErrorIs(c.Get(ctx, key, &testCacheStruct{}), ErrUnavailable)
+	client.AssertNumberOfCalls(t.T(), "Ping", 1)
+
+	// Cooldown elapsed, probe succeeds and closes.
+	now = now.Add(time.Second)
+	failing = false
+	t.ErrorIs(c.Get(ctx, key, &testCacheStruct{}), ErrNotFound)
+	client.AssertNumberOfCalls(t.T(), "Ping", 2)
+	client.AssertNumberOfCalls(t.T(), "Get", 3)
+}
+
+func TestBreaker_Record(t *testing.T) {
+	b := &breaker{opts: BreakerOptions{Threshold: 2}, now: time.Now}
+	assert.False(t, b.record(io.EOF))
+	assert.False(t, b.record(ErrNotFound))
+	assert.False(t, b.record(io.EOF))
+	assert.True(t, b.record(io.EOF))
+	assert.False(t, b.record(io.EOF))
+	assert.True(t, b.open)
+}
+
+// openBreaker returns a breaker that is open for the
+// duration of a test.
+func (t *CacheTestSuite) openBreaker() *breaker {
+	now := time.Now()
+	return &breaker{
+		opts:     BreakerOptions{Threshold: 1, Cooldown: time.Hour},
+		open:     true,
+		openedAt: now,
+		now:      func() time.Time { return now },
+	}
+}
+
+func TestBreaker_Nil(t *testing.T) {
+	var b *breaker
+	assert.True(t, b.allow(context.TODO(), nil))
+	assert.False(t, b.record(io.EOF))
+}
+
+func TestBreaker_Unavailable(t *testing.T) {
+	b := &breaker{}
+	assert.ErrorIs(t, b.unavailable(OpGet), ErrUnavailable)
+	assert.NoError(t, b.unavailable(OpSet))
+	assert.ErrorIs(t, b.unavailable(OpIncr), ErrUnavailable)
+	assert.ErrorIs(t, b.unavailable(OpUpdate), ErrUnavailable)
+	assert.ErrorIs(t, b.unavailable(OpAdd), ErrUnavailable)
+	assert.ErrorIs(t, b.unavailable(OpReplace), ErrUnavailable)
+	b.opts.FailOpen = true
+	assert.ErrorIs(t, b.unavailable(OpGet), ErrNotFound)
+	assert.ErrorIs(t, b.unavailable(OpIncr), ErrUnavailable)
+}
+
+func TestIsFailure(t *testing.T) {
+	tt := map[string]struct {
+		input error
+		want  bool
+	}{
+		"Nil":          {nil, false},
+		"Not Found":    {ErrNotFound, false},
+		"Encode":       {&EncodeError{Err: errors.New("err")}, false},
+		"Decode":       {&DecodeError{Err: errors.New("err")}, false},
+		"Key Too Long": {fmt.Errorf("%w of 1", ErrKeyTooLong), false},
+		"Too Large":    {&ValueTooLargeError{}, false},
+		"Canceled":     {context.Canceled, false},
+		"Redis Reply":  {redis.Nil, false},
+		"Stream":       {ioError{io.ErrShortWrite}, false},
+		"EOF":          {io.EOF, true},
+		"Deadline":     {context.DeadlineExceeded, true},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.want, isFailure(test.input))
+		})
+	}
+}
@@ -0,0 +1,326 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// chunkSuffix is appended to a key, followed by the id of
+	// the write and the index of the chunk, to obtain the
+	// keys holding the chunks of its value.
+	chunkSuffix = ":redigo:chunk:"
+	// manifestMaxLen is the maximum length of a manifest, so
+	// it can be read without reading the whole value.
+	manifestMaxLen = 128
+)
+
+// manifestPrefix is stored in place of a chunked value,
+// followed by its manifest.
+var manifestPrefix = []byte("\x00redigo:chunks\x00")
+
+type (
+	// manifest describes a value stored across multiple
+	// chunk keys.
+	manifest struct {
+		// id identifies the write, so the chunks of a value
+		// are never overwritten while they may be read.
+		id string
+		// chunks is the number of chunks.
+		chunks int
+		// size is the size of the value in bytes.
+		size int
+		// sliding is the expiration the chunks are reset to
+		// when read, if sliding expiration is enabled.
+		sliding time.Duration
+	}
+	// chunkWrite holds the chunk keys written along with a
+	// value and those of the value it replaces.
+	chunkWrite struct {
+		keys  []string
+		stale []string
+	}
+)
+
+// WithChunkSize splits values larger than size bytes once
+// encoded across multiple keys, each holding at most size
+// bytes, so large values do not block the Redis event loop
+// or exceed the limits of proxies. A manifest is stored
+// under the key in place of the value, and the chunks share
+// its expiration and tags. Chunks are written and read in a
+// single pipeline.
+//
+// Writes read the manifest of the existing value, so its
+// chunks can be removed once replaced, which adds a round
+// trip. Chunked values are read regardless of the option.
+func WithChunkSize(size int) Option {
+	return func(c *config) {
+		if size <= 0 {
+			c.errs = append(c.errs, errors.New("redigo: chunk size must be positive"))
+			return
+		}
+		c.chunkSize = size
+	}
+}
+
+// chunk writes the chunks of the buffer if it exceeds the
+// chunk size, returning the manifest to store under the
+// prefixed key in its place. Otherwise, the buffer is
+// returned as is. The chunks of the existing value are
+// returned as stale, to be removed once it is replaced.
+func (c *Cache) chunk(ctx context.Context, key string, buf []byte, exp time.Duration, options Options) ([]byte, chunkWrite, error) {
+	if c.cfg.chunkSize == 0 {
+		return buf, chunkWrite{}, nil
+	}
+
+	old, ttl, err := c.readManifest(ctx, key)
+	if err != nil {
+		return nil, chunkWrite{}, err
+	}
+	w := chunkWrite{stale: old.keys(key)}
+
+	if len(buf) <= c.cfg.chunkSize {
+		return buf, w, nil
+	}
+
+	buf, w.keys, err = c.writeChunks(ctx, key, buf, exp, ttl, options)
+	if err != nil {
+		return nil, chunkWrite{}, err
+	}
+
+	return buf, w, nil
+}
+
+// writeChunks stores the buffer across chunk keys of the
+// prefixed key, returning the manifest and the chunk keys.
+// The chunks are stored with the expiration of the value.
+func (c *Cache) writeChunks(ctx context.Context, key string, buf []byte, exp, ttl time.Duration, options Options) ([]byte, []string, error) {
+	m, err := c.newManifest(exp)
+	if err != nil {
+		return nil, nil, err
+	}
+	size := c.cfg.chunkSize
+	m.chunks = (len(buf) + size - 1) / size
+	m.size = len(buf)
+
+	args := chunkArgs(exp, ttl, options)
+	keys := m.keys(key)
+	err = c.pipeChunks(ctx, keys, func(pipe redis.Pipeliner, i int, k string) {
+		end := (i + 1) * size
+		if end > len(buf) {
+			end = len(buf)
+		}
+		pipe.SetArgs(ctx, k, buf[i*size:end], args)
+	})
+	if err != nil {
+		c.deleteChunks(ctx, keys)
+		return nil, nil, err
+	}
+
+	return m.encode(), keys, nil
+}
+
+// newManifest returns an empty manifest for a new write of
+// a value with the expiration passed.
+func (c *Cache) newManifest(exp time.Duration) (manifest, error) {
+	id, err := newChunkID()
+	if err != nil {
+		return manifest{}, err
+	}
+	m := manifest{id: id}
+	if c.cfg.sliding && exp > 0 {
+		m.sliding = exp
+	}
+	return m, nil
+}
+
+// chunkArgs returns the arguments for SET of a chunk of a
+// value stored with the expiration and options passed. As
+// chunk keys are new, KeepTTL is replaced by the remaining
+// ttl of the value.
+func chunkArgs(exp, ttl time.Duration, options Options) redis.SetArgs {
+	args := setArgs(exp, options)
+	if args.KeepTTL {
+		args.KeepTTL = false
+		if ttl > 0 {
+			args.TTL = ttl
+		}
+	}
+	return args
+}
+
+// readChunks retrieves and joins the chunks of the value
+// described by the manifest, resetting their expiration if
+// sliding expiration is enabled. ErrNotFound is returned if
+// any chunk no longer exists.
+func (c *Cache) readChunks(ctx context.Context, key string, m manifest) ([]byte, error) {
+	cmds := make([]*redis.StringCmd, m.chunks)
+	err := c.pipeChunks(ctx, m.keys(key), func(pipe redis.Pipeliner, i int, k string) {
+		cmds[i] = pipe.Get(ctx, k)
+		if m.sliding > 0 {
+			pipe.PExpire(ctx, k, m.sliding)
+		}
+	})
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 0, m.size)
+	for _, cmd := range cmds {
+		b, _ := cmd.Bytes()
+		buf = append(buf, b...)
+	}
+	if len(buf) != m.size {
+		return nil, ErrNotFound
+	}
+
+	return buf, nil
+}
+
+// readManifest reads the manifest of the prefixed key, along
+// with its remaining ttl. The manifest is empty if the key
+// does not exist or does not hold a chunked value.
+func (c *Cache) readManifest(ctx context.Context, key string) (manifest, time.Duration, error) {
+	var (
+		head *redis.StringCmd
+		ttl  *redis.DurationCmd
+	)
+	err := c.retry(ctx, func() error {
+		_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			head = pipe.GetRange(ctx, key, 0, manifestMaxLen-1)
+			ttl = pipe.PTTL(ctx, key)
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return manifest{}, 0, err
+	}
+	m, _ := parseManifest([]byte(head.Val()))
+	return m, ttl.Val(), nil
+}
+
+// loadManifest returns the manifest of the value of the
+// prefixed key if chunking is enabled, which is empty if
+// the value is not chunked.
+func (c *Cache) loadManifest(ctx context.Context, key string) (manifest, error) {
+	if c.cfg.chunkSize == 0 {
+		return manifest{}, nil
+	}
+	m, _, err := c.readManifest(ctx, key)
+	return m, err
+}
+
+// deleteChunks removes the chunk keys passed, logging any
+// error as leftover chunks expire along with their value.
+func (c *Cache) deleteChunks(ctx context.Context, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	err := c.retry(ctx, func() error {
+		return c.client.Del(ctx, keys...).Err()
+	})
+	if err != nil {
+		c.cfg.logger.Printf("redigo: error deleting chunks %s: %s", keys[0], err.Error())
+	}
+}
+
+// pipeChunks calls fn with each chunk key and its index
+// within a single pipeline.
+func (c *Cache) pipeChunks(ctx context.Context, keys []string, fn func(pipe redis.Pipeliner, i int, key string)) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.retry(ctx, func() error {
+		_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i, k := range keys {
+				fn(pipe, i, k)
+			}
+			return nil
+		})
+		return err
+	})
+}
+
+// encode returns the manifest as stored in Redis.
+func (m manifest) encode() []byte {
+	fields := []string{
+		m.id,
+		strconv.Itoa(m.chunks),
+		strconv.Itoa(m.size),
+		strconv.FormatInt(m.sliding.Milliseconds(), 10),
+	}
+	return append(append([]byte{}, manifestPrefix...), strings.Join(fields, ":")...)
+}
+
+// keys returns the chunk keys of the prefixed key, which
+// is empty for an empty manifest.
+func (m manifest) keys(key string) []string {
+	if m.chunks == 0 {
+		return nil
+	}
+	keys := make([]string, m.chunks)
+	for i := range keys {
+		keys[i] = m.key(key, i)
+	}
+	return keys
+}
+
+// key returns the key of the chunk of the prefixed key at
+// index i.
+func (m manifest) key(key string, i int) string {
+	return key + chunkSuffix + m.id + ":" + strconv.Itoa(i)
+}
+
+// parseManifest parses the manifest from data read from
+// Redis, reporting whether it holds one.
+func parseManifest(data []byte) (manifest, bool) {
+	if !bytes.HasPrefix(data, manifestPrefix) {
+		return manifest{}, false
+	}
+	fields := strings.Split(string(data[len(manifestPrefix):]), ":")
+	if len(fields) != 4 || fields[0] == "" {
+		return manifest{}, false
+	}
+	chunks, err := strconv.Atoi(fields[1])
+	if err != nil || chunks <= 0 {
+		return manifest{}, false
+	}
+	size, err := strconv.Atoi(fields[2])
+	if err != nil || size < 0 {
+		return manifest{}, false
+	}
+	sliding, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return manifest{}, false
+	}
+	return manifest{
+		id:      fields[0],
+		chunks:  chunks,
+		size:    size,
+		sliding: time.Duration(sliding) * time.Millisecond,
+	}, true
+}
+
+// newChunkID returns a random id for the chunks of a write.
+func newChunkID() (string, error) {
+	b := make([]byte, 8)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"github.com/alicebob/miniredis/v2"
+	"strings"
+	"time"
+)
+
+// largeValue encodes to more than the chunk size used in
+// the chunking tests.
+var largeValue = strings.Repeat("redigo", 100)
+
+func (t *CacheTestSuite) TestChunking() {
+	t.Run("Chunked", func() {
+		c, mr := t.SetupRedis(WithChunkSize(100))
+		t.NoError(c.Set(ctx, key, largeValue, Options{Expiration: time.Minute}))
+
+		m, ok := parseManifest([]byte(t.mustGet(mr, key)))
+		t.True(ok)
+		t.Equal(7, m.chunks)
+		for _, k := range m.keys(key) {
+			t.Equal(time.Minute, mr.TTL(k))
+		}
+
+		var got string
+		t.NoError(c.Get(ctx, key, &got))
+		t.Equal(largeValue, got)
+	})
+
+	t.Run("Below Chunk Size", func() {
+		c, mr := t.SetupRedis(WithChunkSize(100))
+		t.NoError(c.Set(ctx, key, "small", Options{}))
+		t.Len(mr.Keys(), 1)
+	})
+
+	t.Run("Overwrite", func() {
+		c, mr := t.SetupRedis(WithChunkSize(100))
+		t.NoError(c.Set(ctx, key, largeValue, Options{}))
+		t.NoError(c.Set(ctx, key, largeValue+largeValue, Options{}))
+		t.Len(t.chunkKeys(mr), 13)
+		t.NoError(c.Set(ctx, key, "small", Options{}))
+		t.Empty(t.chunkKeys(mr))
+	})
+
+	t.Run("Missing Chunk", func() {
+		c, mr := t.SetupRedis(WithChunkSize(100))
+		t.NoError(c.Set(ctx, key, largeValue, Options{}))
+		mr.Del(t.chunkKeys(mr)[0])
+		var got string
+		t.ErrorIs(c.Get(ctx, key, &got), ErrNotFound)
+	})
+
+	t.Run("Tags", func() {
+		c, mr := t.SetupRedis(WithChunkSize(100))
+		t.NoError(c.Set(ctx, key, largeValue, Options{Tags: []string{tag}}))
+		members, err := mr.Members(tag)
+		t.NoError(err)
+		t.Len(members, 8)
+		c.Invalidate(ctx, []string{tag})
+		t.Empty(mr.Keys())
+	})
+
+	t.Run("Delete", func() {
+		c, mr := t.SetupRedis(WithChunkSize(100))
+		t.NoError(c.Set(ctx, key, largeValue, Options{}))
+		t.NoError(c.Delete(ctx, key))
+		t.Empty(mr.Keys())
+	})
+
+	t.Run("Add Rejected", func() {
+		c, mr := t.SetupRedis(WithChunkSize(100))
+		t.NoError(c.Set(ctx, key, "small", Options{}))
+		ok, err := c.Add(ctx, key, largeValue, Options{})
+		t.NoError(err)
+		t.False(ok)
+		t.Len(mr.Keys(), 1)
+	})
+
+	t.Run("Keep TTL", func() {
+		c, mr := t.SetupRedis(WithChunkSize(100))
+		t.NoError(c.Set(ctx, key, "small", Options{Expiration: time.Minute}))
+		t.NoError(c.Set(ctx, key, largeValue, Options{KeepTTL: true}))
+		t.Equal(time.Minute, mr.TTL(key))
+		for _, k := range t.chunkKeys(mr) {
+			t.Equal(time.Minute, mr.TTL(k))
+		}
+	})
+
+	t.Run("Touch And Persist", func() {
+		c, mr := t.SetupRedis(WithChunkSize(100))
+		t.NoError(c.Set(ctx, key, largeValue, Options{Expiration: time.Minute}))
+		t.NoError(c.Touch(ctx, key, time.Hour))
+		for _, k := range t.chunkKeys(mr) {
+			t.Equal(time.Hour, mr.TTL(k))
+		}
+		t.NoError(c.Persist(ctx, key))
+		for _, k := range t.chunkKeys(mr) {
+			t.Equal(time.Duration(0), mr.TTL(k))
+		}
+	})
+
+	t.Run("Sliding", func() {
+		c, mr := t.SetupRedis(WithChunkSize(100), WithSlidingExpiration())
+		t.NoError(c.Set(ctx, key, largeValue, Options{Expiration: time.Minute}))
+		mr.FastForward(50 * time.Second)
+		var got string
+		t.NoError(c.Get(ctx, key, &got))
+		for _, k := range t.chunkKeys(mr) {
+			t.Equal(time.Minute, mr.TTL(k))
+		}
+	})
+
+	t.Run("Update", func() {
+		c, mr := t.SetupRedis(WithChunkSize(100))
+		t.NoError(c.Set(ctx, key, largeValue, Options{}))
+		var got string
+		err := c.Update(ctx, key, &got, func(current any) (any, Options, error) {
+			return *current.(*string) + largeValue, Options{}, nil
+		})
+		t.NoError(err)
+		t.Equal(largeValue+largeValue, got)
+		t.Len(t.chunkKeys(mr), 13)
+	})
+
+	t.Run("Unchunked Read", func() {
+		c, _ := t.SetupRedis(WithChunkSize(100))
+		t.NoError(c.Set(ctx, key, largeValue, Options{}))
+		c.cfg.chunkSize = 0
+		var got string
+		t.NoError(c.Get(ctx, key, &got))
+		t.Equal(largeValue, got)
+	})
+}
+
+func (t *CacheTestSuite) TestMaxValueSize() {
+	c, mr := t.SetupRedis(WithMaxValueSize(100))
+
+	err := c.Set(ctx, key, largeValue, Options{})
+	var target *ValueTooLargeError
+	t.ErrorAs(err, &target)
+	t.Equal(key, target.Key)
+	t.Equal(100, target.Max)
+	t.False(mr.Exists(key))
+
+	t.NoError(c.Set(ctx, key, "small", Options{}))
+}
+
+func (t *CacheTestSuite) TestParseManifest() {
+	tt := map[string]struct {
+		input string
+		want  manifest
+		ok    bool
+	}{
+		"Valid":          {string(manifestPrefix) + "id:2:150:60000", manifest{id: "id", chunks: 2, size: 150, sliding: time.Minute}, true},
+		"Value":          {"value", manifest{}, false},
+		"Missing Fields": {string(manifestPrefix) + "id:2", manifest{}, false},
+		"Zero Chunks":    {string(manifestPrefix) + "id:0:0:0", manifest{}, false},
+		"Invalid Size":   {string(manifestPrefix) + "id:2:size:0", manifest{}, false},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func() {
+			got, ok := parseManifest([]byte(test.input))
+			t.Equal(test.ok, ok)
+			t.Equal(test.want, got)
+			if ok {
+				got, _ = parseManifest(got.encode())
+				t.Equal(test.want, got)
+			}
+		})
+	}
+}
+
+// chunkKeys returns the chunk keys in the in-memory
+// Redis server.
+func (t *CacheTestSuite) chunkKeys(mr *miniredis.Miniredis) []string {
+	var keys []string
+	for _, k := range mr.Keys() {
+		if strings.Contains(k, chunkSuffix) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
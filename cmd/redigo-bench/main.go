@@ -0,0 +1,206 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Command redigo-bench benchmarks encoding and decoding with
+// every RediGo encoder across payload sizes, from 2^0 to
+// 2^max map entries. The results are written to the output
+// directory as results.csv and results.json, and rendered
+// as the charts Encode.svg and Decode.svg.
+//
+// Usage:
+//
+//	go run ./cmd/redigo-bench -dir graph -benchtime 200ms
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/ainsleyclark/redigo"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type (
+	// encoder is a named encoder to benchmark.
+	encoder struct {
+		name string
+		enc  redigo.Encoder
+	}
+	// Result is the outcome of a single benchmark.
+	Result struct {
+		Encoder     string  `json:"encoder"`
+		Operation   string  `json:"operation"`
+		Size        int     `json:"size"`
+		NsPerOp     float64 `json:"ns_per_op"`
+		BytesPerOp  int64   `json:"bytes_per_op"`
+		AllocsPerOp int64   `json:"allocs_per_op"`
+	}
+)
+
+const (
+	// opEncode is the Operation of encoding benchmarks.
+	opEncode = "Encode"
+	// opDecode is the Operation of decoding benchmarks.
+	opDecode = "Decode"
+)
+
+// encoders are the encoders benchmarked. The Protocol
+// Buffers and raw encoders are excluded, as they cannot
+// encode the maps used as payloads.
+var encoders = []encoder{
+	{"JSON", redigo.NewJSONEncoder()},
+	{"Gob", redigo.NewGobEncoder()},
+	{"Gob Stream", redigo.NewGobStreamEncoder()},
+	{"Message Pack", redigo.NewMessagePackEncoder()},
+	{"Go JSON", redigo.NewGoJSONEncoder()},
+	{"Go JSON Pooled", redigo.NewPooledGoJSONEncoder()},
+	{"Segment JSON", redigo.NewSegmentJSONEncoder()},
+	{"CBOR", redigo.NewCBOREncoder()},
+	{"CBOR Deterministic", redigo.NewDeterministicCBOREncoder()},
+}
+
+func main() {
+	testing.Init()
+
+	var (
+		dir       = flag.String("dir", "graph", "directory to write the results and charts to")
+		max       = flag.Int("max", 10, "largest payload size as a power of two")
+		benchtime = flag.String("benchtime", "100ms", "run time of each benchmark")
+	)
+	flag.Parse()
+
+	err := flag.Set("test.benchtime", *benchtime)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	results, err := run(*max, os.Stderr)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	err = write(*dir, results)
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// run benchmarks every encoder for both operations with
+// payload sizes from 2^0 to 2^max, logging progress to w.
+func run(max int, w io.Writer) ([]Result, error) {
+	var results []Result
+	for _, op := range []string{opEncode, opDecode} {
+		for _, e := range encoders {
+			for i := 0; i <= max; i++ {
+				size := 1 << i
+				r, err := benchmark(e, op, size)
+				if err != nil {
+					return nil, err
+				}
+				fmt.Fprintf(w, "%s/%s/%d\t%.0f ns/op\t%d B/op\t%d allocs/op\n",
+					op, e.name, size, r.NsPerOp, r.BytesPerOp, r.AllocsPerOp)
+				results = append(results, r)
+			}
+		}
+	}
+	return results, nil
+}
+
+// benchmark runs a single benchmark of the operation with
+// a payload of the given size.
+func benchmark(e encoder, op string, size int) (Result, error) {
+	payload := createMap(size)
+	buf, err := e.enc.Encode(payload)
+	if err != nil {
+		return Result{}, fmt.Errorf("encoding with %s: %w", e.name, err)
+	}
+
+	br := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if op == opEncode {
+				_, err = e.enc.Encode(payload)
+			} else {
+				m := make(map[int64]float64)
+				err = e.enc.Decode(buf, &m)
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("%s with %s: %w", op, e.name, err)
+	}
+
+	return Result{
+		Encoder:     e.name,
+		Operation:   op,
+		Size:        size,
+		NsPerOp:     float64(br.T.Nanoseconds()) / float64(br.N),
+		BytesPerOp:  br.AllocedBytesPerOp(),
+		AllocsPerOp: br.AllocsPerOp(),
+	}, nil
+}
+
+// write writes the results and charts to the directory.
+func write(dir string, results []Result) error {
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	files := map[string]func(w io.Writer) error{
+		"results.csv":  func(w io.Writer) error { return writeCSV(w, results) },
+		"results.json": func(w io.Writer) error { return writeJSON(w, results) },
+		"Encode.svg":   func(w io.Writer) error { return renderSVG(w, opEncode, filter(results, opEncode)) },
+		"Decode.svg":   func(w io.Writer) error { return renderSVG(w, opDecode, filter(results, opDecode)) },
+	}
+
+	for name, fn := range files {
+		err := writeFile(filepath.Join(dir, name), fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeFile creates the file at path and writes to it
+// using fn.
+func writeFile(path string, fn func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = fn(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// filter returns the results of the operation.
+func filter(results []Result, op string) []Result {
+	var filtered []Result
+	for _, r := range results {
+		if r.Operation == op {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// createMap returns a payload with size entries.
+func createMap(size int) map[int64]float64 {
+	m := make(map[int64]float64, size)
+	for i := 0; i < size; i++ {
+		m[int64(i)] = float64(i)
+	}
+	return m
+}
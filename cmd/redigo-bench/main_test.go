@@ -0,0 +1,94 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var results = []Result{
+	{Encoder: "JSON", Operation: opEncode, Size: 1, NsPerOp: 150, BytesPerOp: 64, AllocsPerOp: 2},
+	{Encoder: "JSON", Operation: opEncode, Size: 2, NsPerOp: 300, BytesPerOp: 96, AllocsPerOp: 3},
+	{Encoder: "Gob", Operation: opEncode, Size: 1, NsPerOp: 900, BytesPerOp: 512, AllocsPerOp: 20},
+	{Encoder: "Gob", Operation: opEncode, Size: 2, NsPerOp: 1000, BytesPerOp: 540, AllocsPerOp: 21},
+	{Encoder: "JSON", Operation: opDecode, Size: 1, NsPerOp: 200, BytesPerOp: 80, AllocsPerOp: 4},
+}
+
+func TestBenchmark(t *testing.T) {
+	benchtime := flag.Lookup("test.benchtime").Value.String()
+	assert.NoError(t, flag.Set("test.benchtime", "10x"))
+	defer flag.Set("test.benchtime", benchtime)
+
+	for _, op := range []string{opEncode, opDecode} {
+		r, err := benchmark(encoders[0], op, 4)
+		assert.NoError(t, err)
+		assert.Equal(t, "JSON", r.Encoder)
+		assert.Equal(t, op, r.Operation)
+		assert.Equal(t, 4, r.Size)
+		assert.Greater(t, r.NsPerOp, 0.0)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, write(dir, results))
+	for _, name := range []string{"results.csv", "results.json", "Encode.svg", "Decode.svg"} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		assert.NoError(t, err)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	buf := bytes.Buffer{}
+	assert.NoError(t, writeCSV(&buf, results[:1]))
+	want := "encoder,operation,size,ns_per_op,bytes_per_op,allocs_per_op\nJSON,Encode,1,150.0,64,2\n"
+	assert.Equal(t, want, buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	buf := bytes.Buffer{}
+	assert.NoError(t, writeJSON(&buf, results))
+	var got []Result
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, results, got)
+}
+
+func TestRenderSVG(t *testing.T) {
+	buf := bytes.Buffer{}
+	assert.NoError(t, renderSVG(&buf, opEncode, filter(results, opEncode)))
+	got := buf.String()
+	assert.True(t, strings.HasPrefix(got, "<svg"))
+	assert.Equal(t, 2, strings.Count(got, "<polyline"))
+	assert.Contains(t, got, ">Gob</text>")
+	assert.Contains(t, got, ">1µs</text>")
+}
+
+func TestNiceCeil(t *testing.T) {
+	tt := map[float64]float64{
+		0:    1,
+		0.3:  0.5,
+		1:    1,
+		1.5:  2,
+		3:    5,
+		7:    10,
+		1200: 2000,
+	}
+	for input, want := range tt {
+		assert.InDelta(t, want, niceCeil(input), 1e-9)
+	}
+}
+
+func TestFormatNs(t *testing.T) {
+	assert.Equal(t, "500ns", formatNs(500))
+	assert.Equal(t, "1.5µs", formatNs(1500))
+	assert.Equal(t, "2ms", formatNs(2e6))
+}
@@ -0,0 +1,171 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// chartWidth and chartHeight are the dimensions of the
+	// rendered charts.
+	chartWidth, chartHeight = 800, 480
+	// chartLeft, chartRight, chartTop and chartBottom are
+	// the margins around the plot area.
+	chartLeft, chartRight, chartTop, chartBottom = 70, 170, 40, 50
+	// chartTicks is the number of ticks on the y axis.
+	chartTicks = 5
+)
+
+// chartColours are the line colours of each encoder, in
+// the order they appear in the results.
+var chartColours = []string{
+	"#4285f4", "#ea4335", "#fbbc04", "#34a853", "#ff6d01",
+	"#46bdc6", "#7baaf7", "#f07b72", "#9334e6", "#185abc",
+}
+
+// writeCSV writes the results as CSV with a header row.
+func writeCSV(w io.Writer, results []Result) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"encoder", "operation", "size", "ns_per_op", "bytes_per_op", "allocs_per_op"})
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		err := cw.Write([]string{
+			r.Encoder,
+			r.Operation,
+			strconv.Itoa(r.Size),
+			strconv.FormatFloat(r.NsPerOp, 'f', 1, 64),
+			strconv.FormatInt(r.BytesPerOp, 10),
+			strconv.FormatInt(r.AllocsPerOp, 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeJSON writes the results as an indented JSON array.
+func writeJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(results)
+}
+
+// renderSVG renders the results as a line chart of ns/op
+// by payload size, with a line per encoder. Sizes are
+// plotted on a log2 scale.
+func renderSVG(w io.Writer, title string, results []Result) error {
+	var (
+		names  []string
+		series = make(map[string][]Result)
+		maxExp = 0
+		maxNs  = 0.0
+	)
+	for _, r := range results {
+		if _, ok := series[r.Encoder]; !ok {
+			names = append(names, r.Encoder)
+		}
+		series[r.Encoder] = append(series[r.Encoder], r)
+		if exp := int(math.Log2(float64(r.Size))); exp > maxExp {
+			maxExp = exp
+		}
+		maxNs = math.Max(maxNs, r.NsPerOp)
+	}
+	maxNs = niceCeil(maxNs)
+
+	plotWidth := float64(chartWidth - chartLeft - chartRight)
+	plotHeight := float64(chartHeight - chartTop - chartBottom)
+	x := func(size int) float64 {
+		if maxExp == 0 {
+			return chartLeft
+		}
+		return chartLeft + math.Log2(float64(size))/float64(maxExp)*plotWidth
+	}
+	y := func(ns float64) float64 {
+		return chartTop + plotHeight - ns/maxNs*plotHeight
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" font-family="sans-serif" font-size="12">`+"\n",
+		chartWidth, chartHeight, chartWidth, chartHeight)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", chartWidth, chartHeight)
+	fmt.Fprintf(&b, `<text x="%d" y="24" font-size="16" fill="#333333">%s (ns/op)</text>`+"\n", chartLeft, html.EscapeString(title))
+
+	// Grid lines and y axis labels.
+	for i := 0; i <= chartTicks; i++ {
+		ns := maxNs / chartTicks * float64(i)
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#cccccc"/>`+"\n",
+			chartLeft, y(ns), chartLeft+plotWidth, y(ns))
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end" fill="#333333">%s</text>`+"\n",
+			chartLeft-8, y(ns)+4, formatNs(ns))
+	}
+
+	// X axis labels.
+	for exp := 0; exp <= maxExp; exp++ {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle" fill="#333333">%d</text>`+"\n",
+			x(1<<exp), chartHeight-chartBottom+18, 1<<exp)
+	}
+	fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle" fill="#333333">Payload size (map entries)</text>`+"\n",
+		chartLeft+plotWidth/2, chartHeight-12)
+
+	// Lines and legend.
+	for i, name := range names {
+		colour := chartColours[i%len(chartColours)]
+		points := make([]string, len(series[name]))
+		for j, r := range series[name] {
+			points[j] = fmt.Sprintf("%.1f,%.1f", x(r.Size), y(r.NsPerOp))
+		}
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n",
+			strings.Join(points, " "), colour)
+
+		ly := chartTop + 20*i
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%d" width="12" height="12" fill="%s"/>`+"\n",
+			chartLeft+plotWidth+16, ly, colour)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" fill="#333333">%s</text>`+"\n",
+			chartLeft+plotWidth+34, ly+10, html.EscapeString(name))
+	}
+
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// niceCeil rounds v up to 1, 2 or 5 times a power of ten,
+// so axis ticks fall on round numbers.
+func niceCeil(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+	exp := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*exp >= v {
+			return m * exp
+		}
+	}
+	return 10 * exp
+}
+
+// formatNs formats a duration in nanoseconds for axis labels.
+func formatNs(ns float64) string {
+	switch {
+	case ns >= 1e6:
+		return strconv.FormatFloat(ns/1e6, 'f', -1, 64) + "ms"
+	case ns >= 1e3:
+		return strconv.FormatFloat(ns/1e3, 'f', -1, 64) + "µs"
+	}
+	return strconv.FormatFloat(ns, 'f', -1, 64) + "ns"
+}
@@ -0,0 +1,202 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"reflect"
+	"strconv"
+)
+
+// numberEscape prefixes encoded values that would otherwise
+// be read as a plain number stored by the counter methods,
+// such as 53 encoded by Message Pack as the byte '5'.
+var numberEscape = []byte("\x00redigo:enc\x00")
+
+// incrScript increments KEYS[1] using the command passed as
+// ARGV[1] by ARGV[2]. If the key was created, its expiration
+// is set using the command passed as ARGV[3], if any, with
+// ARGV[4]. The new value is returned along with 1 if the key
+// was created, or 0 if it existed.
+var incrScript = redis.NewScript(`
+local created = redis.call('EXISTS', KEYS[1]) == 0
+local value = redis.call(ARGV[1], KEYS[1], ARGV[2])
+if not created then
+	return {value, 0}
+end
+if ARGV[3] ~= '' then
+	redis.call(ARGV[3], KEYS[1], ARGV[4])
+end
+return {value, 1}
+`)
+
+// Incr increments the integer value of the key by one,
+// returning the new value. See IncrBy.
+func (c *Cache) Incr(ctx context.Context, key string, options Options) (int64, error) {
+	return c.IncrBy(ctx, key, 1, options)
+}
+
+// Decr decrements the integer value of the key by one,
+// returning the new value. See IncrBy.
+func (c *Cache) Decr(ctx context.Context, key string, options Options) (int64, error) {
+	return c.IncrBy(ctx, key, -1, options)
+}
+
+// IncrBy increments the integer value of the key by n,
+// returning the new value. If the key does not exist, it is
+// created with a value of zero before being incremented,
+// and the expiration and tags from options are applied.
+// The expiration of existing keys is left untouched.
+//
+// Counters are stored as plain numbers rather than using the
+// Encoder, they can be retrieved with Get into a pointer to
+// any integer or float type. Increments are not retried, as
+// they are not idempotent.
+func (c *Cache) IncrBy(ctx context.Context, key string, n int64, options Options) (int64, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	res, err := c.incr(ctx, key, "INCRBY", n, options)
+	if err != nil {
+		return 0, err
+	}
+	v, _ := res.(int64)
+	return v, nil
+}
+
+// IncrByFloat increments the float value of the key by n,
+// returning the new value. It behaves as IncrBy otherwise.
+func (c *Cache) IncrByFloat(ctx context.Context, key string, n float64, options Options) (float64, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	res, err := c.incr(ctx, key, "INCRBYFLOAT", strconv.FormatFloat(n, 'f', -1, 64), options)
+	if err != nil || res == nil {
+		return 0, err
+	}
+	s, _ := res.(string)
+	return strconv.ParseFloat(s, 64)
+}
+
+// incr runs the increment command with the argument n
+// against the key, without acquiring the lock. The reply of
+// the command is returned, or nil if the breaker is open.
+func (c *Cache) incr(ctx context.Context, key, cmd string, n any, options Options) (any, error) {
+	err := options.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := timeoutOverride(ctx, options.Timeout)
+	defer cancel()
+
+	var value any
+	e := &Event{Operation: OpIncr, Key: key, Tags: c.tags(options.Tags)}
+
+	err = c.do(ctx, e, func(ctx context.Context, e *Event) error {
+		k, err := c.key(key)
+		if err != nil {
+			return err
+		}
+
+		args := []any{cmd, n, "", ""}
+		if exp := c.expiration(options); !options.ExpireAt.IsZero() {
+			args[2], args[3] = "PEXPIREAT", options.ExpireAt.UnixMilli()
+		} else if exp > 0 {
+			args[2], args[3] = "PEXPIRE", exp.Milliseconds()
+		}
+
+		res, err := incrScript.Run(ctx, c.client, []string{k}, args...).Slice()
+		if err != nil {
+			return err
+		}
+		value = res[0]
+
+		if created, _ := res[1].(int64); created == 0 {
+			e.Hit = true
+			return nil
+		}
+		c.setTags(ctx, e.Tags, k)
+
+		return nil
+	})
+
+	return value, err
+}
+
+// escapeNumber prepends numberEscape to the encoded value if
+// it is a plain number which does not parse back into the
+// value, so it is decoded with the Encoder rather than as
+// a counter. Encoders which store numbers as plain numbers,
+// such as JSON, are left untouched and can be incremented.
+// Strings and byte slices are never escaped, as they are
+// not decoded as counters.
+func escapeNumber(buf []byte, value any) []byte {
+	if _, err := strconv.ParseFloat(string(buf), 64); err != nil {
+		return buf
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if isBytes(rv) {
+		return buf
+	}
+	if rv.IsValid() {
+		parsed := reflect.New(rv.Type())
+		if decodeNumber(buf, parsed.Interface()) && parsed.Elem().Interface() == rv.Interface() {
+			return buf
+		}
+	}
+	return append(append(make([]byte, 0, len(numberEscape)+len(buf)), numberEscape...), buf...)
+}
+
+// isBytes reports whether the value is a string or byte
+// slice, including named types based on one.
+func isBytes(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.String:
+		return true
+	case reflect.Slice:
+		return rv.Type().Elem().Kind() == reflect.Uint8
+	}
+	return false
+}
+
+// decodeNumber parses data into v if v is a pointer to an
+// integer or float and data is a plain number, as stored by
+// the counter methods. It reports whether v was set.
+func decodeNumber(data []byte, v any) bool {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return false
+	}
+	elem := rv.Elem()
+	s := string(data)
+
+	switch elem.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, elem.Type().Bits())
+		if err != nil {
+			return false
+		}
+		elem.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, elem.Type().Bits())
+		if err != nil {
+			return false
+		}
+		elem.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, elem.Type().Bits())
+		if err != nil {
+			return false
+		}
+		elem.SetFloat(n)
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,176 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func (t *CacheTestSuite) TestCache_Incr() {
+	c, mr := t.SetupRedis(WithPrefix("prefix"))
+	opts := Options{Expiration: time.Minute, Tags: []string{tag}}
+
+	n, err := c.Incr(ctx, key, opts)
+	t.NoError(err)
+	t.Equal(int64(1), n)
+	t.Equal(time.Minute, mr.TTL("prefix:"+key))
+	t.True(mr.Exists("prefix:" + tag))
+
+	mr.FastForward(time.Second * 10)
+	n, err = c.IncrBy(ctx, key, 5, Options{Expiration: time.Hour})
+	t.NoError(err)
+	t.Equal(int64(6), n)
+	t.Equal(time.Second*50, mr.TTL("prefix:"+key))
+
+	n, err = c.Decr(ctx, key, opts)
+	t.NoError(err)
+	t.Equal(int64(5), n)
+
+	got, err := mr.Get("prefix:" + key)
+	t.NoError(err)
+	t.Equal("5", got)
+
+	var i int
+	t.NoError(c.Get(ctx, key, &i))
+	t.Equal(5, i)
+
+	var u uint8
+	t.NoError(c.Get(ctx, key, &u))
+	t.Equal(uint8(5), u)
+}
+
+func (t *CacheTestSuite) TestCache_IncrByFloat() {
+	c, mr := t.SetupRedis()
+
+	f, err := c.IncrByFloat(ctx, key, 1.5, Options{ExpireAt: time.Now().Add(time.Hour)})
+	t.NoError(err)
+	t.Equal(1.5, f)
+	t.InDelta(time.Hour, mr.TTL(key), float64(time.Second))
+
+	f, err = c.IncrByFloat(ctx, key, 0.25, Options{})
+	t.NoError(err)
+	t.Equal(1.75, f)
+
+	var got float64
+	t.NoError(c.Get(ctx, key, &got))
+	t.Equal(1.75, got)
+}
+
+func (t *CacheTestSuite) TestCache_Incr_Error() {
+	t.Run("Not Integer", func() {
+		c, mr := t.SetupRedis()
+		t.NoError(mr.Set(key, "value"))
+		_, err := c.Incr(ctx, key, Options{})
+		t.Error(err)
+	})
+
+	t.Run("Invalid Options", func() {
+		c, _ := t.SetupRedis()
+		_, err := c.Incr(ctx, key, Options{Expiration: time.Minute, KeepTTL: true})
+		t.Error(err)
+	})
+
+	t.Run("Key Too Long", func() {
+		c, _ := t.SetupRedis(WithMaxKeyLength(1))
+		_, err := c.IncrByFloat(ctx, key, 1, Options{})
+		t.ErrorIs(err, ErrKeyTooLong)
+	})
+}
+
+func TestDecodeNumber(t *testing.T) {
+	var (
+		i   int64
+		u   uint
+		f   float32
+		s   string
+		str testCacheStruct
+	)
+
+	assert.True(t, decodeNumber([]byte("-10"), &i))
+	assert.Equal(t, int64(-10), i)
+	assert.True(t, decodeNumber([]byte("10"), &u))
+	assert.Equal(t, uint(10), u)
+	assert.True(t, decodeNumber([]byte("1.5"), &f))
+	assert.Equal(t, float32(1.5), f)
+
+	assert.False(t, decodeNumber([]byte("-10"), &u))
+	assert.False(t, decodeNumber([]byte("1.5"), &i))
+	assert.False(t, decodeNumber([]byte("10"), &s))
+	assert.False(t, decodeNumber([]byte("10"), &str))
+	assert.False(t, decodeNumber([]byte("10"), i))
+	assert.False(t, decodeNumber([]byte("10"), nil))
+}
+
+func (t *CacheTestSuite) TestCache_Get_EncodedNumber() {
+	tt := map[string]struct {
+		enc   Encoder
+		input int
+	}{
+		"Message Pack": {NewMessagePackEncoder(), 53},
+		"CBOR":         {NewCBOREncoder(), -17},
+		"JSON":         {NewJSONEncoder(), 53},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func() {
+			mr := miniredis.RunT(t.T())
+			c, err := New(&redis.Options{Addr: mr.Addr()}, test.enc)
+			t.NoError(err)
+			t.NoError(c.Set(ctx, key, test.input, Options{}))
+			var got int
+			t.NoError(c.Get(ctx, key, &got))
+			t.Equal(test.input, got)
+		})
+	}
+
+	t.Run("JSON Incremented", func() {
+		mr := miniredis.RunT(t.T())
+		c, err := New(&redis.Options{Addr: mr.Addr()}, NewJSONEncoder())
+		t.NoError(err)
+		t.NoError(c.Set(ctx, key, 53, Options{}))
+		n, err := c.Incr(ctx, key, Options{})
+		t.NoError(err)
+		t.Equal(int64(54), n)
+	})
+
+	t.Run("Raw", func() {
+		mr := miniredis.RunT(t.T())
+		c, err := New(&redis.Options{Addr: mr.Addr()}, NewRawEncoder())
+		t.NoError(err)
+		for _, input := range []string{"12345", "3.14", "NaN"} {
+			t.NoError(c.Set(ctx, key, input, Options{}))
+			t.Equal(input, t.mustGet(mr, key))
+			var got string
+			t.NoError(c.Get(ctx, key, &got))
+			t.Equal(input, got)
+		}
+	})
+
+	t.Run("Counter", func() {
+		mr := miniredis.RunT(t.T())
+		c, err := New(&redis.Options{Addr: mr.Addr()}, NewMessagePackEncoder())
+		t.NoError(err)
+		_, err = c.IncrBy(ctx, key, 5, Options{})
+		t.NoError(err)
+		var got int
+		t.NoError(c.Get(ctx, key, &got))
+		t.Equal(5, got)
+	})
+}
+
+func TestEscapeNumber(t *testing.T) {
+	type text string
+	assert.Equal(t, []byte("53"), escapeNumber([]byte("53"), 53))
+	assert.Equal(t, []byte("1.5"), escapeNumber([]byte("1.5"), 1.5))
+	assert.Equal(t, []byte("value"), escapeNumber([]byte("value"), "value"))
+	assert.Equal(t, append(append([]byte{}, numberEscape...), '5'), escapeNumber([]byte("5"), 53))
+	assert.Equal(t, []byte("5"), escapeNumber([]byte("5"), "5"))
+	assert.Equal(t, []byte("3.14"), escapeNumber([]byte("3.14"), []byte("3.14")))
+	assert.Equal(t, []byte("NaN"), escapeNumber([]byte("NaN"), text("NaN")))
+}
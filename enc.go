@@ -6,10 +6,17 @@ package redigo
 
 import (
 	"bytes"
+	"encoding"
 	"encoding/gob"
 	"encoding/json"
+	"fmt"
+	"github.com/fxamacker/cbor/v2"
 	gojson "github.com/goccy/go-json"
+	segjson "github.com/segmentio/encoding/json"
 	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"reflect"
+	"sync"
 )
 
 // Encoder defines methods for encoding and decoding
@@ -27,19 +34,65 @@ func NewGobEncoder() Encoder {
 // gobEnc implements the encoder interface.
 type gobEnc struct{}
 
+var (
+	// gobBufferPool holds buffers reused between calls to
+	// gobEnc.Encode, so they do not need to grow each time.
+	gobBufferPool = sync.Pool{
+		New: func() any {
+			return new(bytes.Buffer)
+		},
+	}
+	// gobReaderPool holds readers reused between calls
+	// to gobEnc.Decode.
+	gobReaderPool = sync.Pool{
+		New: func() any {
+			return new(bytes.Reader)
+		},
+	}
+)
+
+// maxPooledBuffer is the capacity above which buffers are
+// not returned to a pool, so a single large value does not
+// hold on to memory indefinitely.
+const maxPooledBuffer = 64 << 10
+
+// putBuffer returns the buffer to the pool if it is not
+// larger than maxPooledBuffer.
+func putBuffer(pool *sync.Pool, buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	pool.Put(buf)
+}
+
 func (g gobEnc) Encode(value any) ([]byte, error) {
-	var buf bytes.Buffer
-	enc := gob.NewEncoder(&buf)
+	buf := gobBufferPool.Get().(*bytes.Buffer)
+	defer putBuffer(&gobBufferPool, buf)
+	buf.Reset()
+
+	// A new encoder is required per value, as gob only
+	// transmits type information once per stream.
+	enc := gob.NewEncoder(buf)
 	err := enc.Encode(value)
 	if err != nil {
 		return nil, err
 	}
-	return buf.Bytes(), nil
+
+	out := make([]byte, buf.Len())
+	copy(out, buf.Bytes())
+
+	return out, nil
 }
 
 func (g gobEnc) Decode(data []byte, value any) error {
-	buf := bytes.NewBuffer(data)
-	dec := gob.NewDecoder(buf)
+	r := gobReaderPool.Get().(*bytes.Reader)
+	defer func() {
+		r.Reset(nil)
+		gobReaderPool.Put(r)
+	}()
+	r.Reset(data)
+
+	dec := gob.NewDecoder(r)
 	return dec.Decode(value)
 }
 
@@ -76,6 +129,40 @@ func (m msgEnc) Decode(data []byte, value any) error {
 	return msgpack.Unmarshal(data, value)
 }
 
+// NewCBOREncoder returns a new CBOR encoder for RediGo.
+func NewCBOREncoder() Encoder {
+	return &cborEnc{mode: cborMode}
+}
+
+// NewDeterministicCBOREncoder returns a new CBOR encoder
+// for RediGo using the Core Deterministic Encoding rules,
+// so equal values always produce the same bytes, such as
+// for keys being compared or hashed. Map keys are sorted,
+// making it slower than NewCBOREncoder.
+func NewDeterministicCBOREncoder() Encoder {
+	return &cborEnc{mode: cborDetMode}
+}
+
+var (
+	// cborMode is the default CBOR encoding mode.
+	cborMode, _ = cbor.EncOptions{}.EncMode()
+	// cborDetMode is the deterministic CBOR encoding mode.
+	cborDetMode, _ = cbor.CoreDetEncOptions().EncMode()
+)
+
+// cborEnc implements the encoder interface.
+type cborEnc struct {
+	mode cbor.EncMode
+}
+
+func (c cborEnc) Encode(value any) ([]byte, error) {
+	return c.mode.Marshal(value)
+}
+
+func (c cborEnc) Decode(data []byte, value any) error {
+	return cbor.Unmarshal(data, value)
+}
+
 // NewGoJSONEncoder returns a new Go JSON
 // encoder for RediGo.
 func NewGoJSONEncoder() Encoder {
@@ -92,3 +179,179 @@ func (g goJSONEnc) Encode(value any) ([]byte, error) {
 func (g goJSONEnc) Decode(data []byte, value any) error {
 	return gojson.Unmarshal(data, value)
 }
+
+// NewProtoEncoder returns a new Protocol Buffers encoder
+// for RediGo. Values must implement proto.Message, any
+// other type returns an error.
+func NewProtoEncoder() Encoder {
+	return &protoEnc{}
+}
+
+// NewProtoEncoderWithFallback returns a new Protocol Buffers
+// encoder for RediGo that uses the fallback encoder for
+// values that do not implement proto.Message.
+func NewProtoEncoderWithFallback(fallback Encoder) Encoder {
+	return &protoEnc{fallback: fallback}
+}
+
+// protoEnc implements the encoder interface.
+type protoEnc struct {
+	fallback Encoder
+}
+
+func (p protoEnc) Encode(value any) ([]byte, error) {
+	msg, ok := value.(proto.Message)
+	if ok {
+		return proto.Marshal(msg)
+	}
+	if p.fallback != nil {
+		return p.fallback.Encode(value)
+	}
+	return nil, fmt.Errorf("redigo: proto encoder cannot encode type %T, value must implement proto.Message", value)
+}
+
+func (p protoEnc) Decode(data []byte, value any) error {
+	msg, ok := value.(proto.Message)
+	if ok {
+		return proto.Unmarshal(data, msg)
+	}
+	if p.fallback != nil {
+		return p.fallback.Decode(data, value)
+	}
+	return fmt.Errorf("redigo: proto encoder cannot decode into type %T, value must implement proto.Message", value)
+}
+
+// NewRawEncoder returns a new encoder for RediGo that
+// stores []byte and string values as is, so they can be
+// read by other services sharing the same Redis. Values
+// implementing encoding.BinaryMarshaler or
+// encoding.TextMarshaler are stored using their marshalled
+// form, and decoded with the corresponding unmarshaler.
+// Any other type returns an error.
+func NewRawEncoder() Encoder {
+	return &rawEnc{}
+}
+
+// rawEnc implements the encoder interface.
+type rawEnc struct{}
+
+func (r rawEnc) Encode(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	case encoding.BinaryMarshaler:
+		return v.MarshalBinary()
+	case encoding.TextMarshaler:
+		return v.MarshalText()
+	}
+	// Named types such as template.HTML.
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.Kind() == reflect.String:
+		return []byte(rv.String()), nil
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		return rv.Bytes(), nil
+	}
+	return nil, fmt.Errorf("redigo: raw encoder cannot encode type %T", value)
+}
+
+func (r rawEnc) Decode(data []byte, value any) error {
+	switch v := value.(type) {
+	case *[]byte:
+		*v = append([]byte(nil), data...)
+		return nil
+	case *string:
+		*v = string(data)
+		return nil
+	case encoding.BinaryUnmarshaler:
+		return v.UnmarshalBinary(data)
+	case encoding.TextUnmarshaler:
+		return v.UnmarshalText(data)
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		elem := rv.Elem()
+		switch {
+		case elem.Kind() == reflect.String:
+			elem.SetString(string(data))
+			return nil
+		case elem.Kind() == reflect.Slice && elem.Type().Elem().Kind() == reflect.Uint8:
+			elem.SetBytes(append([]byte(nil), data...))
+			return nil
+		}
+	}
+	return fmt.Errorf("redigo: raw encoder cannot decode into type %T", value)
+}
+
+// NewPooledGoJSONEncoder returns a new Go JSON encoder for
+// RediGo that reuses buffers and encoders between calls,
+// reducing allocations when encoding to a single copy of
+// the result, which is owned by the caller. Values passed
+// to Decode do not escape to the heap.
+func NewPooledGoJSONEncoder() Encoder {
+	return &pooledGoJSONEnc{}
+}
+
+// pooledGoJSONEnc implements the encoder interface.
+type pooledGoJSONEnc struct{}
+
+// goJSONBuffer is a buffer with an encoder writing to it,
+// reused between calls via goJSONPool.
+type goJSONBuffer struct {
+	buf bytes.Buffer
+	enc *gojson.Encoder
+}
+
+var goJSONPool = sync.Pool{
+	New: func() any {
+		b := &goJSONBuffer{}
+		b.enc = gojson.NewEncoder(&b.buf)
+		return b
+	},
+}
+
+func (p pooledGoJSONEnc) Encode(value any) ([]byte, error) {
+	b := goJSONPool.Get().(*goJSONBuffer)
+	defer func() {
+		if b.buf.Cap() <= maxPooledBuffer {
+			goJSONPool.Put(b)
+		}
+	}()
+	b.buf.Reset()
+
+	err := b.enc.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+
+	// Encode terminates the value with a new line.
+	data := bytes.TrimSuffix(b.buf.Bytes(), []byte{'\n'})
+	out := make([]byte, len(data))
+	copy(out, data)
+
+	return out, nil
+}
+
+func (p pooledGoJSONEnc) Decode(data []byte, value any) error {
+	return gojson.UnmarshalNoEscape(data, value)
+}
+
+// NewSegmentJSONEncoder returns a new JSON encoder for
+// RediGo backed by github.com/segmentio/encoding, a faster
+// drop in replacement for encoding/json.
+func NewSegmentJSONEncoder() Encoder {
+	return &segmentJSONEnc{}
+}
+
+// segmentJSONEnc implements the encoder interface.
+type segmentJSONEnc struct{}
+
+func (s segmentJSONEnc) Encode(value any) ([]byte, error) {
+	return segjson.Marshal(value)
+}
+
+func (s segmentJSONEnc) Decode(data []byte, value any) error {
+	return segjson.Unmarshal(data, value)
+}
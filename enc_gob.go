@@ -0,0 +1,303 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"hash/fnv"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// gobStreamMagic prefixes values encoded by the gob stream
+// encoder. Gob streams never start with a zero byte, so
+// values encoded by NewGobEncoder can be told apart.
+const gobStreamMagic = "\x00rg"
+
+const (
+	// gobModePlain is the mode of values encoded as a
+	// standalone gob stream, used for types containing
+	// interfaces.
+	gobModePlain byte = iota
+	// gobModeStream is the mode of values encoded with
+	// a cached type preamble.
+	gobModeStream
+)
+
+type (
+	// gobStreamEnc implements the encoder interface.
+	gobStreamEnc struct {
+		types    sync.Map // reflect.Type to *gobTypeStream
+		decoders sync.Map // preamble hash to *sync.Pool of *gobDecoder
+	}
+	// gobTypeStream is a persistent gob stream for a single
+	// type, which has already transmitted the type preamble.
+	gobTypeStream struct {
+		mtx    sync.Mutex
+		plain  bool
+		enc    *gob.Encoder
+		buf    bytes.Buffer
+		header []byte
+	}
+	// gobDecoder is a decoder that has received a type
+	// preamble, reading values from a swappable reader.
+	gobDecoder struct {
+		r   swapReader
+		dec *gob.Decoder
+	}
+	// swapReader is an io.Reader and io.ByteReader whose
+	// data can be replaced between reads. Implementing
+	// io.ByteReader prevents gob from buffering it.
+	swapReader struct {
+		data []byte
+		off  int
+	}
+)
+
+// NewGobStreamEncoder returns a new Gob encoder for RediGo
+// that avoids the fixed cost of gob type descriptors.
+//
+// The type preamble is encoded once per Go type and cached,
+// so only the value itself is encoded on each call. Values
+// carry the preamble along with its hash, keeping them
+// decodable by any process. Decoders that have already
+// received a preamble are reused, so decoding skips it and
+// the type compilation it triggers.
+//
+// Types containing interfaces are encoded as standalone gob
+// streams, as the concrete types they hold may differ
+// between values. NewGobEncoder values are decoded as is.
+func NewGobStreamEncoder() Encoder {
+	return &gobStreamEnc{}
+}
+
+func (g *gobStreamEnc) Encode(value any) ([]byte, error) {
+	t := reflect.TypeOf(value)
+	if t == nil {
+		return nil, errors.New("redigo: gob cannot encode nil value")
+	}
+
+	s, ok := g.types.Load(t)
+	if !ok {
+		s, _ = g.types.LoadOrStore(t, &gobTypeStream{plain: hasInterface(t, map[reflect.Type]bool{})})
+	}
+
+	return s.(*gobTypeStream).encode(value)
+}
+
+func (g *gobStreamEnc) Decode(data []byte, value any) error {
+	if !bytes.HasPrefix(data, []byte(gobStreamMagic)) || len(data) == len(gobStreamMagic) {
+		return gobEnc{}.Decode(data, value)
+	}
+
+	data = data[len(gobStreamMagic):]
+	mode, data := data[0], data[1:]
+	if mode == gobModePlain {
+		return gobEnc{}.Decode(data, value)
+	} else if mode != gobModeStream {
+		return errors.New("redigo: unknown gob stream mode")
+	}
+
+	if len(data) < 8 {
+		return io.ErrUnexpectedEOF
+	}
+	hash := binary.BigEndian.Uint64(data)
+	data = data[8:]
+	n, k := binary.Uvarint(data)
+	if k <= 0 || n > uint64(len(data)-k) {
+		return errors.New("redigo: invalid gob stream preamble")
+	}
+	preamble, msg := data[k:k+int(n)], data[k+int(n):]
+
+	p, ok := g.decoders.Load(hash)
+	if !ok {
+		p, _ = g.decoders.LoadOrStore(hash, &sync.Pool{})
+	}
+	pool := p.(*sync.Pool)
+
+	d, ok := pool.Get().(*gobDecoder)
+	if !ok {
+		if gobHash(preamble) != hash {
+			return errors.New("redigo: gob stream preamble does not match hash")
+		}
+		d = &gobDecoder{}
+		d.dec = gob.NewDecoder(&d.r)
+		// A new decoder receives the preamble along with
+		// the value, after which it can be reused.
+		msg = data[k:]
+	}
+
+	d.r.reset(msg)
+	err := d.dec.Decode(value)
+	done := d.r.off == len(d.r.data)
+	d.r.reset(nil)
+
+	// The decoder is discarded if it failed, or did not read
+	// the whole value, as its state may be inconsistent.
+	if err != nil {
+		return err
+	}
+	if done {
+		pool.Put(d)
+	}
+
+	return nil
+}
+
+// encode encodes the value, transmitting the type preamble
+// if it has not been already.
+func (s *gobTypeStream) encode(value any) ([]byte, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.plain {
+		buf, err := gobEnc{}.Encode(value)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]byte, 0, len(gobStreamMagic)+1+len(buf))
+		out = append(out, gobStreamMagic...)
+		out = append(out, gobModePlain)
+		return append(out, buf...), nil
+	}
+
+	if s.enc == nil {
+		err := s.init(value)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		s.buf.Reset()
+		err := s.enc.Encode(value)
+		if err != nil {
+			// The stream may have been partially written,
+			// so it is started again on the next call.
+			s.enc = nil
+			return nil, err
+		}
+	}
+
+	out := make([]byte, len(s.header)+s.buf.Len())
+	n := copy(out, s.header)
+	copy(out[n:], s.buf.Bytes())
+
+	return out, nil
+}
+
+// init starts the stream and builds the header from the
+// preamble written before the first value. The value is
+// encoded twice, the preamble being the difference between
+// the two, leaving the value in the buffer.
+func (s *gobTypeStream) init(value any) error {
+	s.buf.Reset()
+	enc := gob.NewEncoder(&s.buf)
+	err := enc.Encode(value)
+	if err != nil {
+		return err
+	}
+	first := make([]byte, s.buf.Len())
+	copy(first, s.buf.Bytes())
+
+	s.buf.Reset()
+	err = enc.Encode(value)
+	if err != nil {
+		return err
+	}
+	preamble := first[:len(first)-s.buf.Len()]
+
+	var (
+		hash [8]byte
+		size [binary.MaxVarintLen64]byte
+	)
+	binary.BigEndian.PutUint64(hash[:], gobHash(preamble))
+	n := binary.PutUvarint(size[:], uint64(len(preamble)))
+
+	header := make([]byte, 0, len(gobStreamMagic)+1+len(hash)+n+len(preamble))
+	header = append(header, gobStreamMagic...)
+	header = append(header, gobModeStream)
+	header = append(header, hash[:]...)
+	header = append(header, size[:n]...)
+	header = append(header, preamble...)
+
+	s.enc = enc
+	s.header = header
+
+	return nil
+}
+
+// gobHash returns the hash of the preamble.
+func gobHash(preamble []byte) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(preamble)
+	return h.Sum64()
+}
+
+var (
+	gobEncoderType      = reflect.TypeOf((*gob.GobEncoder)(nil)).Elem()
+	binaryMarshalerType = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// hasInterface reports whether values of the type may hold
+// interfaces, which gob encodes along with the type
+// descriptor of their concrete type.
+func hasInterface(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	if t.Implements(gobEncoderType) || t.Implements(binaryMarshalerType) || t.Implements(textMarshalerType) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return hasInterface(t.Elem(), seen)
+	case reflect.Map:
+		return hasInterface(t.Key(), seen) || hasInterface(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			// Unexported fields are ignored by gob.
+			f := t.Field(i)
+			if f.IsExported() && hasInterface(f.Type, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// reset replaces the data read.
+func (r *swapReader) reset(data []byte) {
+	r.data = data
+	r.off = 0
+}
+
+func (r *swapReader) Read(p []byte) (int, error) {
+	if r.off >= len(r.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.data[r.off:])
+	r.off += n
+	return n, nil
+}
+
+func (r *swapReader) ReadByte() (byte, error) {
+	if r.off >= len(r.data) {
+		return 0, io.EOF
+	}
+	b := r.data[r.off]
+	r.off++
+	return b, nil
+}
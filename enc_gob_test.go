@@ -0,0 +1,167 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGobStreamEncode(t *testing.T) {
+	UtilTestEncode(t, NewGobStreamEncoder(), gobStreamMagic+"\x01"+"\xcb\xf2\x9c\xe4\x84\x22\x23\x25\x00"+"\b\f\x00\x05hello")
+}
+
+func TestGobStreamEncoder(t *testing.T) {
+	t.Run("Round Trip", func(t *testing.T) {
+		enc := NewGobStreamEncoder()
+		for i := 0; i < 5; i++ {
+			want := createUser(int64(i))
+			buf, err := enc.Encode(want)
+			assert.NoError(t, err)
+			got := benchUser{}
+			assert.NoError(t, enc.Decode(buf, &got))
+			assert.Equal(t, want, got)
+		}
+	})
+
+	t.Run("Preamble Cached", func(t *testing.T) {
+		enc := NewGobStreamEncoder()
+		first, err := enc.Encode(value)
+		assert.NoError(t, err)
+		second, err := enc.Encode(value)
+		assert.NoError(t, err)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("Other Instance", func(t *testing.T) {
+		buf, err := NewGobStreamEncoder().Encode(createUsers(3))
+		assert.NoError(t, err)
+		dec := NewGobStreamEncoder()
+		for i := 0; i < 3; i++ {
+			got := []benchUser{}
+			assert.NoError(t, dec.Decode(buf, &got))
+			assert.Equal(t, createUsers(3), got)
+		}
+	})
+
+	t.Run("Pointer", func(t *testing.T) {
+		enc := NewGobStreamEncoder()
+		want := &testCacheStruct{Name: "name", Value: 1}
+		buf, err := enc.Encode(want)
+		assert.NoError(t, err)
+		got := &testCacheStruct{}
+		assert.NoError(t, enc.Decode(buf, got))
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("Interface", func(t *testing.T) {
+		type withAny struct{ Value any }
+		enc := NewGobStreamEncoder()
+		for _, v := range []any{"hello", 1} {
+			buf, err := enc.Encode(withAny{Value: v})
+			assert.NoError(t, err)
+			assert.Equal(t, gobModePlain, buf[len(gobStreamMagic)])
+			got := withAny{}
+			assert.NoError(t, NewGobStreamEncoder().Decode(buf, &got))
+			assert.Equal(t, v, got.Value)
+		}
+	})
+
+	t.Run("Gob Encoder Values", func(t *testing.T) {
+		buf, err := NewGobEncoder().Encode(value)
+		assert.NoError(t, err)
+		got := testCacheStruct{}
+		assert.NoError(t, NewGobStreamEncoder().Decode(buf, &got))
+		assert.Equal(t, value, got)
+	})
+
+	t.Run("Invalid Hash", func(t *testing.T) {
+		buf, err := NewGobStreamEncoder().Encode(value)
+		assert.NoError(t, err)
+		buf[len(gobStreamMagic)+1]++
+		assert.ErrorContains(t, NewGobStreamEncoder().Decode(buf, &testCacheStruct{}), "does not match hash")
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		assert.Error(t, NewGobStreamEncoder().Decode([]byte(gobStreamMagic+"\x01\x00"), &testCacheStruct{}))
+		assert.Error(t, NewGobStreamEncoder().Decode([]byte(gobStreamMagic+"\x01\x00\x00\x00\x00\x00\x00\x00\x00\xff"), &testCacheStruct{}))
+		assert.Error(t, NewGobStreamEncoder().Decode([]byte(gobStreamMagic+"\x02"), &testCacheStruct{}))
+	})
+
+	t.Run("Decode Error Discards Decoder", func(t *testing.T) {
+		enc := NewGobStreamEncoder()
+		buf, err := enc.Encode(value)
+		assert.NoError(t, err)
+		assert.Error(t, enc.Decode(buf, &[]string{}))
+		got := testCacheStruct{}
+		assert.NoError(t, enc.Decode(buf, &got))
+		assert.Equal(t, value, got)
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		_, err := NewGobStreamEncoder().Encode(nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		enc := NewGobStreamEncoder()
+		wg := sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					want := map[string]int{fmt.Sprint(i): j}
+					buf, err := enc.Encode(want)
+					assert.NoError(t, err)
+					got := map[string]int{}
+					assert.NoError(t, enc.Decode(buf, &got))
+					assert.Equal(t, want, got)
+				}
+			}(i)
+		}
+		wg.Wait()
+	})
+}
+
+func TestHasInterface(t *testing.T) {
+	type (
+		node struct {
+			Next *node
+		}
+		unexported struct {
+			Name string
+			any  any
+		}
+	)
+
+	tt := map[string]struct {
+		input any
+		want  bool
+	}{
+		"String":     {"", false},
+		"Struct":     {benchUser{}, false},
+		"Recursive":  {node{}, false},
+		"Marshaler":  {time.Time{}, false},
+		"Unexported": {unexported{}, false},
+		"Any":        {struct{ V any }{}, true},
+		"Slice":      {[]any{}, true},
+		"Map":        {map[string]any{}, true},
+		"Pointer":    {&struct{ V fmt.Stringer }{}, true},
+		"Buffer":     {bytes.Buffer{}, false},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := hasInterface(reflect.TypeOf(test.input), map[reflect.Type]bool{})
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -2,7 +2,12 @@ package redigo
 
 import (
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"html/template"
+	"math/big"
 	"testing"
+	"time"
 )
 
 func UtilTestEncode(t *testing.T, enc Encoder, want string) {
@@ -65,6 +70,121 @@ func TestMessagePackEncode(t *testing.T) {
 	UtilTestEncode(t, NewMessagePackEncoder(), "\xa5hello")
 }
 
+func TestPooledGoJSONEncode(t *testing.T) {
+	UtilTestEncode(t, NewPooledGoJSONEncoder(), "\"hello\"")
+
+	enc := NewPooledGoJSONEncoder()
+	first, err := enc.Encode("first")
+	assert.NoError(t, err)
+	_, err = enc.Encode("second")
+	assert.NoError(t, err)
+	assert.Equal(t, "\"first\"", string(first))
+}
+
+func TestSegmentJSONEncode(t *testing.T) {
+	UtilTestEncode(t, NewSegmentJSONEncoder(), "\"hello\"")
+}
+
+func TestCBOREncode(t *testing.T) {
+	UtilTestEncode(t, NewCBOREncoder(), "ehello")
+}
+
+func TestDeterministicCBOREncode(t *testing.T) {
+	UtilTestEncode(t, NewDeterministicCBOREncoder(), "ehello")
+
+	enc := NewDeterministicCBOREncoder()
+	want, err := enc.Encode(map[string]int{"a": 1, "b": 2, "c": 3})
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		got, err := enc.Encode(map[string]int{"c": 3, "b": 2, "a": 1})
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+}
+
 func TestGoJSONEncode(t *testing.T) {
 	UtilTestEncode(t, NewGoJSONEncoder(), "\"hello\"")
 }
+
+func TestRawEncode(t *testing.T) {
+	enc := NewRawEncoder()
+	num := big.NewInt(100)
+	now := time.Now()
+	bin, _ := now.MarshalBinary()
+
+	t.Run("Encode", func(t *testing.T) {
+		tt := map[string]struct {
+			input any
+			want  any
+		}{
+			"Bytes":            {[]byte("hello"), "hello"},
+			"String":           {"hello", "hello"},
+			"Named String":     {template.HTML("<p>hello</p>"), "<p>hello</p>"},
+			"Binary Marshaler": {now, string(bin)},
+			"Text Marshaler":   {num, "100"},
+			"Error":            {1, "cannot encode type int"},
+		}
+
+		for name, test := range tt {
+			t.Run(name, func(t *testing.T) {
+				got, err := enc.Encode(test.input)
+				if err != nil {
+					assert.Contains(t, err.Error(), test.want)
+					return
+				}
+				assert.Equal(t, test.want, string(got))
+			})
+		}
+	})
+
+	t.Run("Decode", func(t *testing.T) {
+		var (
+			b    []byte
+			s    string
+			html template.HTML
+			n    big.Int
+			i    int
+		)
+		assert.NoError(t, enc.Decode([]byte("hello"), &b))
+		assert.Equal(t, []byte("hello"), b)
+		assert.NoError(t, enc.Decode([]byte("hello"), &s))
+		assert.Equal(t, "hello", s)
+		assert.NoError(t, enc.Decode([]byte("<p>hello</p>"), &html))
+		assert.Equal(t, template.HTML("<p>hello</p>"), html)
+		assert.NoError(t, enc.Decode([]byte("100"), &n))
+		assert.Equal(t, num.String(), n.String())
+		assert.Error(t, enc.Decode([]byte("wrong"), &n))
+		assert.ErrorContains(t, enc.Decode([]byte("1"), &i), "cannot decode into type *int")
+	})
+}
+
+func TestProtoEncode(t *testing.T) {
+	msg := wrapperspb.String("hello")
+	want, err := proto.Marshal(msg)
+	assert.NoError(t, err)
+
+	t.Run("Encode", func(t *testing.T) {
+		got, err := NewProtoEncoder().Encode(msg)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+		_, err = NewProtoEncoder().Encode("hello")
+		assert.ErrorContains(t, err, "must implement proto.Message")
+	})
+
+	t.Run("Decode", func(t *testing.T) {
+		got := &wrapperspb.StringValue{}
+		assert.NoError(t, NewProtoEncoder().Decode(want, got))
+		assert.Equal(t, "hello", got.GetValue())
+		assert.Error(t, NewProtoEncoder().Decode([]byte("wrong"), got))
+		val := ""
+		assert.ErrorContains(t, NewProtoEncoder().Decode(want, &val), "must implement proto.Message")
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		enc := NewProtoEncoderWithFallback(NewJSONEncoder())
+		got, err := enc.Encode(msg)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+		UtilTestEncode(t, enc, "\"hello\"")
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+)
+
+var (
+	// ErrNotFound is returned when a key does not exist in
+	// the cache. It is compatible with redis.Nil, meaning
+	// errors.Is(err, redis.Nil) holds true.
+	ErrNotFound error = notFoundError{}
+	// ErrEncode is the sentinel error matched by all
+	// errors of type EncodeError using errors.Is.
+	ErrEncode = errors.New("redigo: encode error")
+	// ErrDecode is the sentinel error matched by all
+	// errors of type DecodeError using errors.Is.
+	ErrDecode = errors.New("redigo: decode error")
+	// ErrKeyTooLong is returned when a key exceeds the length
+	// set via WithMaxKeyLength.
+	ErrKeyTooLong = errors.New("redigo: key exceeds max length")
+	// ErrUnavailable is returned by Get when the circuit
+	// breaker is open and fail open mode is disabled, and
+	// by the counter methods, Update, Add and Replace whilst
+	// it is open.
+	ErrUnavailable = errors.New("redigo: cache unavailable")
+	// ErrConflict is returned by Update when the key was
+	// modified concurrently on every attempt.
+	ErrConflict = errors.New("redigo: update conflict")
+	// ErrValueTooLarge is the sentinel error matched by all
+	// errors of type ValueTooLargeError using errors.Is.
+	ErrValueTooLarge = errors.New("redigo: value too large")
+)
+
+type (
+	// notFoundError is the underlying type of ErrNotFound.
+	notFoundError struct{}
+	// EncodeError is returned when the Encoder fails to
+	// encode a value for the given key.
+	EncodeError struct {
+		Key string
+		Err error
+	}
+	// DecodeError is returned when the Encoder fails to
+	// decode a value for the given key.
+	DecodeError struct {
+		Key string
+		Err error
+	}
+	// ValueTooLargeError is returned when the encoded value
+	// for the given key exceeds the size set via
+	// WithMaxValueSize.
+	ValueTooLargeError struct {
+		Key  string
+		Size int
+		Max  int
+	}
+)
+
+func (notFoundError) Error() string {
+	return "redigo: key not found"
+}
+
+// Unwrap returns redis.Nil so errors.Is(err, redis.Nil)
+// continues to work for callers that check for it.
+func (notFoundError) Unwrap() error {
+	return redis.Nil
+}
+
+// Error implements the error interface.
+func (e *EncodeError) Error() string {
+	return fmt.Sprintf("redigo: error encoding key %s: %s", e.Key, e.Err.Error())
+}
+
+// Unwrap returns the error from the Encoder.
+func (e *EncodeError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrEncode.
+func (e *EncodeError) Is(target error) bool {
+	return target == ErrEncode
+}
+
+// Error implements the error interface.
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("redigo: error decoding key %s: %s", e.Key, e.Err.Error())
+}
+
+// Unwrap returns the error from the Encoder.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrDecode.
+func (e *DecodeError) Is(target error) bool {
+	return target == ErrDecode
+}
+
+// Error implements the error interface.
+func (e *ValueTooLargeError) Error() string {
+	return fmt.Sprintf("redigo: value of key %s is %d bytes, exceeding max size of %d", e.Key, e.Size, e.Max)
+}
+
+// Is reports whether the target is ErrValueTooLarge.
+func (e *ValueTooLargeError) Is(target error) bool {
+	return target == ErrValueTooLarge
+}
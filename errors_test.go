@@ -0,0 +1,56 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrNotFound(t *testing.T) {
+	assert.EqualError(t, ErrNotFound, "redigo: key not found")
+	assert.ErrorIs(t, ErrNotFound, redis.Nil)
+	assert.ErrorIs(t, fmt.Errorf("wrapped: %w", ErrNotFound), redis.Nil)
+}
+
+func TestEncodeError(t *testing.T) {
+	cause := errors.New("cause")
+	var err error = &EncodeError{Key: "key", Err: cause}
+	assert.EqualError(t, err, "redigo: error encoding key key: cause")
+	assert.ErrorIs(t, err, ErrEncode)
+	assert.ErrorIs(t, err, cause)
+	assert.False(t, errors.Is(err, ErrDecode))
+
+	var target *EncodeError
+	assert.True(t, errors.As(err, &target))
+	assert.Equal(t, "key", target.Key)
+}
+
+func TestDecodeError(t *testing.T) {
+	cause := errors.New("cause")
+	var err error = &DecodeError{Key: "key", Err: cause}
+	assert.EqualError(t, err, "redigo: error decoding key key: cause")
+	assert.ErrorIs(t, err, ErrDecode)
+	assert.ErrorIs(t, err, cause)
+	assert.False(t, errors.Is(err, ErrEncode))
+
+	var target *DecodeError
+	assert.True(t, errors.As(err, &target))
+	assert.Equal(t, "key", target.Key)
+}
+
+func TestValueTooLargeError(t *testing.T) {
+	var err error = &ValueTooLargeError{Key: "key", Size: 20, Max: 10}
+	assert.EqualError(t, err, "redigo: value of key key is 20 bytes, exceeding max size of 10")
+	assert.ErrorIs(t, err, ErrValueTooLarge)
+	assert.False(t, errors.Is(err, ErrEncode))
+
+	var target *ValueTooLargeError
+	assert.True(t, errors.As(err, &target))
+	assert.Equal(t, 20, target.Size)
+}
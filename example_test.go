@@ -15,8 +15,12 @@ import (
 func Example() {
 	ctx := context.Background()
 
-	c := redigo.New(&redis.Options{}, redigo.NewGobEncoder())
-	err := c.Ping(ctx)
+	c, err := redigo.New(&redis.Options{}, redigo.NewGobEncoder())
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	err = c.Ping(ctx)
 	if err != nil {
 		log.Fatalln(err)
 	}
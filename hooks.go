@@ -0,0 +1,164 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type (
+	// Operation is the name of a cache operation passed
+	// to hooks via the Event.
+	Operation string
+	// Event describes a single cache operation. The same
+	// Event is passed to Hook.Before and Hook.After, with
+	// the outcome populated for the latter.
+	Event struct {
+		// Operation is the name of the operation being
+		// performed, such as OpGet.
+		Operation Operation
+		// Key is the key passed to the operation, without
+		// the prefix applied, empty for Invalidate, Flush,
+		// Keys and DeletePattern.
+		Key string
+		// Tags are the tags being set or invalidated.
+		Tags []string
+		// Size is the size of the encoded value in bytes.
+		Size int
+		// Duration is the time taken to perform the
+		// operation, excluding hooks.
+		Duration time.Duration
+		// Hit is true if the key was found in the cache. For
+		// Add and Replace, it reports whether the key existed
+		// before the write.
+		Hit bool
+		// Err is the error returned by the operation, if
+		// any. Misses are reported with ErrNotFound.
+		Err error
+	}
+	// Hook defines the methods for intercepting cache
+	// operations, such as for logging, metrics, tracing
+	// or auditing.
+	Hook interface {
+		// Before is called before the operation is performed.
+		// The context returned is used for the operation and
+		// passed to After.
+		Before(ctx context.Context, e *Event) context.Context
+		// After is called once the operation has completed.
+		After(ctx context.Context, e *Event)
+	}
+)
+
+const (
+	// OpGet is the Operation for Get().
+	OpGet Operation = "get"
+	// OpSet is the Operation for Set().
+	OpSet Operation = "set"
+	// OpAdd is the Operation for Add().
+	OpAdd Operation = "add"
+	// OpReplace is the Operation for Replace().
+	OpReplace Operation = "replace"
+	// OpUpdate is the Operation for Update().
+	OpUpdate Operation = "update"
+	// OpIncr is the Operation for Incr(), IncrBy(),
+	// IncrByFloat() and Decr().
+	OpIncr Operation = "incr"
+	// OpDelete is the Operation for Delete().
+	OpDelete Operation = "delete"
+	// OpExists is the Operation for Exists().
+	OpExists Operation = "exists"
+	// OpTTL is the Operation for TTL().
+	OpTTL Operation = "ttl"
+	// OpTouch is the Operation for Touch().
+	OpTouch Operation = "touch"
+	// OpPersist is the Operation for Persist().
+	OpPersist Operation = "persist"
+	// OpInvalidate is the Operation for Invalidate().
+	OpInvalidate Operation = "invalidate"
+	// OpFlush is the Operation for Flush().
+	OpFlush Operation = "flush"
+	// OpScan is the Operation for each page of keys
+	// retrieved by Keys().
+	OpScan Operation = "scan"
+	// OpDeletePattern is the Operation for each batch of
+	// keys removed by DeletePattern().
+	OpDeletePattern Operation = "delete_pattern"
+)
+
+// isRead reports whether the operation only reads from
+// the cache.
+func (o Operation) isRead() bool {
+	return o == OpGet || o == OpExists || o == OpTTL || o == OpScan
+}
+
+// WithHooks registers hooks that are called around every
+// cache operation. Before hooks are called in the order
+// they are registered and After hooks in reverse.
+func WithHooks(hooks ...Hook) Option {
+	return func(c *config) {
+		for _, hook := range hooks {
+			if hook == nil {
+				c.errs = append(c.errs, errors.New("redigo: hook cannot be nil"))
+				return
+			}
+		}
+		c.hooks = append(c.hooks, hooks...)
+	}
+}
+
+// AddHook registers a hook on the cache, it will be called
+// around every subsequent cache operation.
+func (c *Cache) AddHook(hook Hook) {
+	if hook == nil {
+		return
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.cfg.hooks = append(c.cfg.hooks, hook)
+}
+
+// do performs the operation fn, calling the registered
+// hooks around it and recording metrics for the event.
+// If the circuit breaker is open, fn is not called.
+func (c *Cache) do(ctx context.Context, e *Event, fn func(ctx context.Context, e *Event) error) error {
+	for _, hook := range c.cfg.hooks {
+		ctx = hook.Before(ctx, e)
+	}
+
+	ctx, cancel := c.timeout(ctx, e.Operation)
+	defer cancel()
+
+	start := time.Now()
+	if c.cfg.breaker.allow(ctx, c.ping) {
+		e.Err = fn(ctx, e)
+		if c.cfg.breaker.record(e.Err) {
+			c.cfg.logger.Printf("redigo: circuit breaker opened after %d consecutive failures: %s",
+				c.cfg.breaker.opts.Threshold, e.Err.Error())
+		}
+	} else {
+		e.Err = c.cfg.breaker.unavailable(e.Operation)
+	}
+	e.Duration = time.Since(start)
+
+	c.cfg.stats.event(e)
+	switch {
+	case errors.Is(e.Err, ErrNotFound):
+		if e.Operation == OpGet {
+			c.cfg.metrics.Miss(e.Key)
+		}
+	case e.Err != nil:
+		c.cfg.metrics.Error(string(e.Operation), e.Key, e.Err)
+	case e.Operation == OpGet:
+		c.cfg.metrics.Hit(e.Key)
+	}
+
+	for i := len(c.cfg.hooks) - 1; i >= 0; i-- {
+		c.cfg.hooks[i].After(ctx, e)
+	}
+
+	return e.Err
+}
@@ -0,0 +1,183 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"context"
+	"errors"
+	"github.com/ainsleyclark/redigo/mocks"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/mock"
+)
+
+type (
+	// ctxKey is the type used for context values in hooks.
+	ctxKey struct{}
+	// recordHook is a Hook that records the events and
+	// the order in which it was called.
+	recordHook struct {
+		name   string
+		calls  *[]string
+		events []Event
+	}
+	// recordMetrics is Metrics that records calls.
+	recordMetrics struct {
+		calls []string
+	}
+)
+
+func (r *recordHook) Before(ctx context.Context, e *Event) context.Context {
+	*r.calls = append(*r.calls, "before "+r.name)
+	return context.WithValue(ctx, ctxKey{}, r.name)
+}
+
+func (r *recordHook) After(ctx context.Context, e *Event) {
+	*r.calls = append(*r.calls, "after "+r.name+" "+ctx.Value(ctxKey{}).(string))
+	r.events = append(r.events, *e)
+}
+
+func (r *recordMetrics) Hit(key string) {
+	r.calls = append(r.calls, "hit "+key)
+}
+
+func (r *recordMetrics) Miss(key string) {
+	r.calls = append(r.calls, "miss "+key)
+}
+
+func (r *recordMetrics) Error(op, key string, _ error) {
+	r.calls = append(r.calls, "error "+op+" "+key)
+}
+
+func (t *CacheTestSuite) TestHooks_Order() {
+	var calls []string
+	first, second := &recordHook{name: "first", calls: &calls}, &recordHook{name: "second", calls: &calls}
+
+	c := t.Setup(func(m *mocks.RedisStore, enc *mocks.Encoder) {
+		m.On("Del", mock.Anything, key).
+			Return(redis.NewIntCmd(ctx, nil))
+	})
+	c.cfg.hooks = []Hook{first}
+	c.AddHook(second)
+	c.AddHook(nil)
+
+	err := c.Delete(ctx, key)
+	t.NoError(err)
+	t.Equal([]string{
+		"before first",
+		"before second",
+		"after second second",
+		"after first second",
+	}, calls)
+}
+
+func (t *CacheTestSuite) TestHooks_Events() {
+	tt := map[string]struct {
+		mock    func(m *mocks.RedisStore, enc *mocks.Encoder)
+		call    func(c *Cache)
+		want    Event
+		metrics []string
+	}{
+		"Get Hit": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Get", mock.Anything, key).
+					Return(redis.NewStringResult(string(t.GobBuf), nil))
+				enc.On("Decode", t.GobBuf, mock.Anything).
+					Return(nil)
+			},
+			func(c *Cache) {
+				_ = c.Get(ctx, key, &testCacheStruct{})
+			},
+			Event{Operation: OpGet, Key: key, Size: len(t.GobBuf), Hit: true},
+			[]string{"hit key"},
+		},
+		"Get Miss": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Get", mock.Anything, key).
+					Return(redis.NewStringResult("", redis.Nil))
+			},
+			func(c *Cache) {
+				_ = c.Get(ctx, key, &testCacheStruct{})
+			},
+			Event{Operation: OpGet, Key: key, Err: ErrNotFound},
+			[]string{"miss key"},
+		},
+		"Set": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				enc.On("Encode", value).
+					Return(t.GobBuf, nil)
+				m.On("Set", mock.Anything, key, t.GobBuf, options.Expiration).
+					Return(redis.NewStatusCmd(ctx, nil))
+				m.On("SAdd", mock.Anything, tag, key).
+					Return(redis.NewIntCmd(ctx, nil))
+				m.On("Expire", mock.Anything, tag, DefaultTagExpiration).
+					Return(redis.NewBoolCmd(ctx, true))
+			},
+			func(c *Cache) {
+				_ = c.Set(ctx, key, value, options)
+			},
+			Event{Operation: OpSet, Key: key, Tags: []string{tag}, Size: len(t.GobBuf)},
+			nil,
+		},
+		"Delete Error": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				cmd := redis.NewIntCmd(ctx, nil)
+				cmd.SetErr(errors.New("delete error"))
+				m.On("Del", mock.Anything, key).
+					Return(cmd)
+			},
+			func(c *Cache) {
+				_ = c.Delete(ctx, key)
+			},
+			Event{Operation: OpDelete, Key: key, Err: errors.New("delete error")},
+			[]string{"error delete key"},
+		},
+		"Invalidate": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				cmd := redis.NewStringSliceCmd(ctx)
+				cmd.SetVal([]string{})
+				m.On("SMembers", mock.Anything, tag).
+					Return(cmd)
+				m.On("Del", mock.Anything, tag).
+					Return(redis.NewIntCmd(ctx, nil))
+			},
+			func(c *Cache) {
+				c.Invalidate(ctx, []string{tag})
+			},
+			Event{Operation: OpInvalidate, Tags: []string{tag}},
+			nil,
+		},
+		"Flush": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("FlushAll", mock.Anything).
+					Return(redis.NewStatusCmd(ctx, nil))
+			},
+			func(c *Cache) {
+				c.Flush(ctx)
+			},
+			Event{Operation: OpFlush},
+			nil,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func() {
+			var calls []string
+			hook := &recordHook{name: "hook", calls: &calls}
+			metrics := &recordMetrics{}
+
+			c := t.Setup(test.mock)
+			c.cfg.metrics = metrics
+			c.AddHook(hook)
+			test.call(c)
+
+			t.Len(hook.events, 1)
+			got := hook.events[0]
+			t.Greater(int64(got.Duration), int64(0))
+			got.Duration = 0
+			t.Equal(test.want, got)
+			t.Equal(test.metrics, metrics.calls)
+		})
+	}
+}
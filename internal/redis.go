@@ -17,11 +17,23 @@ type (
 		Ping(ctx context.Context) *redis.StatusCmd
 		Get(ctx context.Context, key string) *redis.StringCmd
 		Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+		SetArgs(ctx context.Context, key string, value interface{}, a redis.SetArgs) *redis.StatusCmd
 		Del(ctx context.Context, keys ...string) *redis.IntCmd
+		Unlink(ctx context.Context, keys ...string) *redis.IntCmd
+		ScanType(ctx context.Context, cursor uint64, match string, count int64, keyType string) *redis.ScanCmd
 		SMembers(ctx context.Context, key string) *redis.StringSliceCmd
 		FlushAll(ctx context.Context) *redis.StatusCmd
 		SAdd(ctx context.Context, key string, members ...interface{}) *redis.IntCmd
 		Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd
+		Exists(ctx context.Context, keys ...string) *redis.IntCmd
+		TTL(ctx context.Context, key string) *redis.DurationCmd
+		Persist(ctx context.Context, key string) *redis.BoolCmd
+		Eval(ctx context.Context, script string, keys []string, args ...interface{}) *redis.Cmd
+		EvalSha(ctx context.Context, sha1 string, keys []string, args ...interface{}) *redis.Cmd
+		ScriptExists(ctx context.Context, hashes ...string) *redis.BoolSliceCmd
+		ScriptLoad(ctx context.Context, script string) *redis.StringCmd
+		Watch(ctx context.Context, fn func(*redis.Tx) error, keys ...string) error
+		Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error)
 		Close() error
 	}
 )
@@ -54,6 +54,79 @@ func (_m *RedisStore) Del(ctx context.Context, keys ...string) *redis.IntCmd {
 	return r0
 }
 
+// Eval provides a mock function with given fields: ctx, script, keys, args
+func (_m *RedisStore) Eval(ctx context.Context, script string, keys []string, args ...interface{}) *redis.Cmd {
+	_va := make([]interface{}, len(args))
+	for _i := range args {
+		_va[_i] = args[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, script)
+	_ca = append(_ca, keys)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *redis.Cmd
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, ...interface{}) *redis.Cmd); ok {
+		r0 = rf(ctx, script, keys, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redis.Cmd)
+		}
+	}
+
+	return r0
+}
+
+// EvalSha provides a mock function with given fields: ctx, sha1, keys, args
+func (_m *RedisStore) EvalSha(ctx context.Context, sha1 string, keys []string, args ...interface{}) *redis.Cmd {
+	_va := make([]interface{}, len(args))
+	for _i := range args {
+		_va[_i] = args[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, sha1)
+	_ca = append(_ca, keys)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *redis.Cmd
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, ...interface{}) *redis.Cmd); ok {
+		r0 = rf(ctx, sha1, keys, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redis.Cmd)
+		}
+	}
+
+	return r0
+}
+
+// Exists provides a mock function with given fields: ctx, keys
+func (_m *RedisStore) Exists(ctx context.Context, keys ...string) *redis.IntCmd {
+	_va := make([]interface{}, len(keys))
+	for _i := range keys {
+		_va[_i] = keys[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *redis.IntCmd
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) *redis.IntCmd); ok {
+		r0 = rf(ctx, keys...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redis.IntCmd)
+		}
+	}
+
+	return r0
+}
+
 // Expire provides a mock function with given fields: ctx, key, expiration
 func (_m *RedisStore) Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd {
 	ret := _m.Called(ctx, key, expiration)
@@ -102,6 +175,22 @@ func (_m *RedisStore) Get(ctx context.Context, key string) *redis.StringCmd {
 	return r0
 }
 
+// Persist provides a mock function with given fields: ctx, key
+func (_m *RedisStore) Persist(ctx context.Context, key string) *redis.BoolCmd {
+	ret := _m.Called(ctx, key)
+
+	var r0 *redis.BoolCmd
+	if rf, ok := ret.Get(0).(func(context.Context, string) *redis.BoolCmd); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redis.BoolCmd)
+		}
+	}
+
+	return r0
+}
+
 // Ping provides a mock function with given fields: ctx
 func (_m *RedisStore) Ping(ctx context.Context) *redis.StatusCmd {
 	ret := _m.Called(ctx)
@@ -118,6 +207,29 @@ func (_m *RedisStore) Ping(ctx context.Context) *redis.StatusCmd {
 	return r0
 }
 
+// Pipelined provides a mock function with given fields: ctx, fn
+func (_m *RedisStore) Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	ret := _m.Called(ctx, fn)
+
+	var r0 []redis.Cmder
+	if rf, ok := ret.Get(0).(func(context.Context, func(redis.Pipeliner) error) []redis.Cmder); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]redis.Cmder)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, func(redis.Pipeliner) error) error); ok {
+		r1 = rf(ctx, fn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SAdd provides a mock function with given fields: ctx, key, members
 func (_m *RedisStore) SAdd(ctx context.Context, key string, members ...interface{}) *redis.IntCmd {
 	var _ca []interface{}
@@ -153,6 +265,61 @@ func (_m *RedisStore) SMembers(ctx context.Context, key string) *redis.StringSli
 	return r0
 }
 
+// ScanType provides a mock function with given fields: ctx, cursor, match, count, keyType
+func (_m *RedisStore) ScanType(ctx context.Context, cursor uint64, match string, count int64, keyType string) *redis.ScanCmd {
+	ret := _m.Called(ctx, cursor, match, count, keyType)
+
+	var r0 *redis.ScanCmd
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, int64, string) *redis.ScanCmd); ok {
+		r0 = rf(ctx, cursor, match, count, keyType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redis.ScanCmd)
+		}
+	}
+
+	return r0
+}
+
+// ScriptExists provides a mock function with given fields: ctx, hashes
+func (_m *RedisStore) ScriptExists(ctx context.Context, hashes ...string) *redis.BoolSliceCmd {
+	_va := make([]interface{}, len(hashes))
+	for _i := range hashes {
+		_va[_i] = hashes[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *redis.BoolSliceCmd
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) *redis.BoolSliceCmd); ok {
+		r0 = rf(ctx, hashes...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redis.BoolSliceCmd)
+		}
+	}
+
+	return r0
+}
+
+// ScriptLoad provides a mock function with given fields: ctx, script
+func (_m *RedisStore) ScriptLoad(ctx context.Context, script string) *redis.StringCmd {
+	ret := _m.Called(ctx, script)
+
+	var r0 *redis.StringCmd
+	if rf, ok := ret.Get(0).(func(context.Context, string) *redis.StringCmd); ok {
+		r0 = rf(ctx, script)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redis.StringCmd)
+		}
+	}
+
+	return r0
+}
+
 // Set provides a mock function with given fields: ctx, key, value, expiration
 func (_m *RedisStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
 	ret := _m.Called(ctx, key, value, expiration)
@@ -169,6 +336,83 @@ func (_m *RedisStore) Set(ctx context.Context, key string, value interface{}, ex
 	return r0
 }
 
+// SetArgs provides a mock function with given fields: ctx, key, value, a
+func (_m *RedisStore) SetArgs(ctx context.Context, key string, value interface{}, a redis.SetArgs) *redis.StatusCmd {
+	ret := _m.Called(ctx, key, value, a)
+
+	var r0 *redis.StatusCmd
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}, redis.SetArgs) *redis.StatusCmd); ok {
+		r0 = rf(ctx, key, value, a)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redis.StatusCmd)
+		}
+	}
+
+	return r0
+}
+
+// TTL provides a mock function with given fields: ctx, key
+func (_m *RedisStore) TTL(ctx context.Context, key string) *redis.DurationCmd {
+	ret := _m.Called(ctx, key)
+
+	var r0 *redis.DurationCmd
+	if rf, ok := ret.Get(0).(func(context.Context, string) *redis.DurationCmd); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redis.DurationCmd)
+		}
+	}
+
+	return r0
+}
+
+// Unlink provides a mock function with given fields: ctx, keys
+func (_m *RedisStore) Unlink(ctx context.Context, keys ...string) *redis.IntCmd {
+	_va := make([]interface{}, len(keys))
+	for _i := range keys {
+		_va[_i] = keys[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *redis.IntCmd
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) *redis.IntCmd); ok {
+		r0 = rf(ctx, keys...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redis.IntCmd)
+		}
+	}
+
+	return r0
+}
+
+// Watch provides a mock function with given fields: ctx, fn, keys
+func (_m *RedisStore) Watch(ctx context.Context, fn func(*redis.Tx) error, keys ...string) error {
+	_va := make([]interface{}, len(keys))
+	for _i := range keys {
+		_va[_i] = keys[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, fn)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(*redis.Tx) error, ...string) error); ok {
+		r0 = rf(ctx, fn, keys...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type NewRedisStoreT interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,156 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+// Loader loads a value from the source of truth when it
+// is not in the cache. Returning ErrNotFound signals that
+// the value does not exist, which is cached if negative
+// caching is enabled via WithNegativeTTL.
+type Loader func(ctx context.Context) (any, error)
+
+var (
+	// tombstone is stored in place of a value to record
+	// that it does not exist.
+	tombstone = []byte("\x00redigo:nil\x00")
+	// errTombstone is returned when a tombstone is found,
+	// it matches ErrNotFound using errors.Is.
+	errTombstone = fmt.Errorf("%w (cached)", ErrNotFound)
+)
+
+// tombstoneLua defines is_tombstone for scripts, which
+// reports whether the key holds the tombstone passed.
+const tombstoneLua = `
+local function is_tombstone(key, tombstone)
+	return redis.call('TYPE', key).ok == 'string'
+		and redis.call('STRLEN', key) == #tombstone
+		and redis.call('GET', key) == tombstone
+end
+`
+
+var (
+	// existsScript returns the number of KEYS that exist and
+	// do not hold the tombstone passed as ARGV[1].
+	existsScript = redis.NewScript(tombstoneLua + `
+local n = 0
+for _, key in ipairs(KEYS) do
+	if redis.call('EXISTS', key) == 1 and not is_tombstone(key, ARGV[1]) then
+		n = n + 1
+	end
+end
+return n
+`)
+	// ttlScript returns the TTL of KEYS[1] in milliseconds as
+	// PTTL does, replying with -2 if it holds the tombstone
+	// passed as ARGV[1].
+	ttlScript = redis.NewScript(tombstoneLua + `
+if is_tombstone(KEYS[1], ARGV[1]) then
+	return -2
+end
+return redis.call('PTTL', KEYS[1])
+`)
+)
+
+// WithNegativeTTL enables negative caching for GetOrLoad.
+// When the Loader returns ErrNotFound, a tombstone is stored
+// for the duration and subsequent calls return ErrNotFound
+// without calling the Loader.
+func WithNegativeTTL(d time.Duration) Option {
+	return func(c *config) {
+		if d <= 0 {
+			c.errs = append(c.errs, errors.New("redigo: negative ttl must be positive"))
+			return
+		}
+		c.negativeTTL = d
+	}
+}
+
+// GetOrLoad retrieves the value of the key into v. If the key
+// does not exist, the Loader is called and its value is stored
+// with the options passed before being decoded into v.
+//
+// If the Loader returns ErrNotFound and negative caching is
+// enabled, a tombstone is stored with the negative TTL and
+// the tags from options. ErrNotFound is returned in both
+// cases. Errors from Get, other than misses, are returned
+// without calling the Loader. If the loaded value could not
+// be stored, the error is logged and v is still populated.
+func (c *Cache) GetOrLoad(ctx context.Context, key string, v any, options Options, loader Loader) error {
+	c.mtx.Lock()
+	err := c.get(ctx, key, v)
+	c.mtx.Unlock()
+	if err == nil || errors.Is(err, errTombstone) || !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	value, err := loader(ctx)
+	if errors.Is(err, ErrNotFound) {
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+		c.setTombstone(ctx, key, options)
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+
+	c.mtx.Lock()
+	buf, _, err := c.set(ctx, OpSet, key, value, options)
+	c.mtx.Unlock()
+	if errors.Is(err, ErrEncode) || errors.Is(err, ErrKeyTooLong) {
+		return err
+	} else if err != nil {
+		c.cfg.logger.Printf("redigo: error storing loaded value for key %s: %s", key, err.Error())
+	}
+
+	// The value is not encoded if it was not stored, such
+	// as when the circuit breaker is open.
+	if buf == nil {
+		buf, err = c.encode(ctx, key, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.decode(ctx, key, buf, v)
+}
+
+// setTombstone stores a tombstone for the key if negative
+// caching is enabled. Errors are logged.
+func (c *Cache) setTombstone(ctx context.Context, key string, options Options) {
+	if c.cfg.negativeTTL <= 0 {
+		return
+	}
+
+	ctx, cancel := timeoutOverride(ctx, options.Timeout)
+	defer cancel()
+
+	e := &Event{Operation: OpSet, Key: key, Tags: c.tags(options.Tags), Size: len(tombstone)}
+
+	err := c.do(ctx, e, func(ctx context.Context, e *Event) error {
+		k, err := c.key(key)
+		if err != nil {
+			return err
+		}
+		_, err = c.write(ctx, k, tombstone, "", Options{Expiration: c.cfg.negativeTTL}, e.Tags)
+		return err
+	})
+	if err != nil {
+		c.cfg.logger.Printf("redigo: error storing tombstone for key %s: %s", key, err.Error())
+	}
+}
+
+// isTombstone reports whether the value retrieved from
+// Redis is a tombstone.
+func isTombstone(buf []byte) bool {
+	return bytes.Equal(buf, tombstone)
+}
@@ -0,0 +1,221 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"context"
+	"errors"
+	"github.com/ainsleyclark/redigo/mocks"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func (t *CacheTestSuite) TestCache_GetOrLoad() {
+	tt := map[string]struct {
+		mock   func(m *mocks.RedisStore, enc *mocks.Encoder)
+		loader Loader
+		want   any
+	}{
+		"Hit": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Get", mock.Anything, key).
+					Return(redis.NewStringResult(string(t.GobBuf), nil))
+			},
+			func(ctx context.Context) (any, error) {
+				return nil, errors.New("loader called")
+			},
+			value,
+		},
+		"Loaded": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Get", mock.Anything, key).
+					Return(redis.NewStringResult("", redis.Nil))
+				m.On("Set", mock.Anything, key, t.GobBuf, options.Expiration).
+					Return(redis.NewStatusCmd(ctx, nil))
+				m.On("SAdd", mock.Anything, tag, key).
+					Return(redis.NewIntCmd(ctx, nil))
+				m.On("Expire", mock.Anything, tag, DefaultTagExpiration).
+					Return(redis.NewBoolCmd(ctx, true))
+			},
+			func(ctx context.Context) (any, error) {
+				return value, nil
+			},
+			value,
+		},
+		"Loaded Store Error": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Get", mock.Anything, key).
+					Return(redis.NewStringResult("", redis.Nil))
+				cmd := redis.NewStatusCmd(ctx)
+				cmd.SetErr(errors.New("set error"))
+				m.On("Set", mock.Anything, key, t.GobBuf, options.Expiration).
+					Return(cmd)
+			},
+			func(ctx context.Context) (any, error) {
+				return value, nil
+			},
+			value,
+		},
+		"Tombstone": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Get", mock.Anything, key).
+					Return(redis.NewStringResult(string(tombstone), nil))
+			},
+			func(ctx context.Context) (any, error) {
+				return nil, errors.New("loader called")
+			},
+			ErrNotFound.Error(),
+		},
+		"Not Found": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Get", mock.Anything, key).
+					Return(redis.NewStringResult("", redis.Nil))
+				m.On("Set", mock.Anything, key, tombstone, time.Minute).
+					Return(redis.NewStatusCmd(ctx, nil))
+				m.On("SAdd", mock.Anything, tag, key).
+					Return(redis.NewIntCmd(ctx, nil))
+				m.On("Expire", mock.Anything, tag, DefaultTagExpiration).
+					Return(redis.NewBoolCmd(ctx, true))
+			},
+			func(ctx context.Context) (any, error) {
+				return nil, ErrNotFound
+			},
+			ErrNotFound.Error(),
+		},
+		"Get Error": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Get", mock.Anything, key).
+					Return(redis.NewStringResult("", errors.New("get error")))
+			},
+			func(ctx context.Context) (any, error) {
+				return nil, errors.New("loader called")
+			},
+			"get error",
+		},
+		"Loader Error": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Get", mock.Anything, key).
+					Return(redis.NewStringResult("", redis.Nil))
+			},
+			func(ctx context.Context) (any, error) {
+				return nil, errors.New("loader error")
+			},
+			"loader error",
+		},
+		"Encode Error": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Get", mock.Anything, key).
+					Return(redis.NewStringResult("", redis.Nil))
+			},
+			func(ctx context.Context) (any, error) {
+				return make(chan int), nil
+			},
+			"error encoding key",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func() {
+			c := t.Setup(test.mock)
+			c.encoder = NewGobEncoder()
+			c.cfg.negativeTTL = time.Minute
+
+			got := testCacheStruct{}
+			err := c.GetOrLoad(ctx, key, &got, options, test.loader)
+			if err != nil {
+				t.Contains(err.Error(), test.want)
+				return
+			}
+			t.Equal(test.want, got)
+		})
+	}
+}
+
+func (t *CacheTestSuite) TestCache_GetOrLoad_Disabled() {
+	c := t.Setup(func(m *mocks.RedisStore, enc *mocks.Encoder) {
+		m.On("Get", mock.Anything, key).
+			Return(redis.NewStringResult("", redis.Nil))
+	})
+
+	err := c.GetOrLoad(ctx, key, &testCacheStruct{}, options, func(ctx context.Context) (any, error) {
+		return nil, ErrNotFound
+	})
+	t.ErrorIs(err, ErrNotFound)
+	c.client.(*mocks.RedisStore).AssertNotCalled(t.T(), "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (t *CacheTestSuite) TestCache_GetOrLoad_Tombstone() {
+	notFound := func(ctx context.Context) (any, error) {
+		return nil, ErrNotFound
+	}
+
+	t.Run("Add", func() {
+		c, mr := t.SetupRedis(WithNegativeTTL(time.Minute))
+		t.ErrorIs(c.GetOrLoad(ctx, key, &testCacheStruct{}, Options{}, notFound), ErrNotFound)
+
+		ok, err := c.Add(ctx, key, value, Options{Expiration: time.Hour})
+		t.NoError(err)
+		t.True(ok)
+		t.Equal(time.Hour, mr.TTL(key))
+
+		got := testCacheStruct{}
+		t.NoError(c.Get(ctx, key, &got))
+		t.Equal(value, got)
+	})
+
+	t.Run("Add Keep TTL", func() {
+		c, mr := t.SetupRedis(WithNegativeTTL(time.Minute))
+		t.ErrorIs(c.GetOrLoad(ctx, key, &testCacheStruct{}, Options{}, notFound), ErrNotFound)
+
+		ok, err := c.Add(ctx, key, value, Options{KeepTTL: true})
+		t.NoError(err)
+		t.True(ok)
+		t.Zero(mr.TTL(key))
+	})
+
+	t.Run("Replace", func() {
+		c, mr := t.SetupRedis(WithNegativeTTL(time.Minute))
+		t.ErrorIs(c.GetOrLoad(ctx, key, &testCacheStruct{}, Options{}, notFound), ErrNotFound)
+
+		ok, err := c.Replace(ctx, key, value, Options{})
+		t.NoError(err)
+		t.False(ok)
+		t.Equal(string(tombstone), t.mustGet(mr, key))
+		t.Equal(time.Minute, mr.TTL(key))
+	})
+
+	t.Run("Exists", func() {
+		c, _ := t.SetupRedis(WithNegativeTTL(time.Minute))
+		t.ErrorIs(c.GetOrLoad(ctx, key, &testCacheStruct{}, Options{}, notFound), ErrNotFound)
+		t.NoError(c.Set(ctx, "other", value, Options{}))
+
+		n, err := c.Exists(ctx, key, "other", "missing")
+		t.NoError(err)
+		t.Equal(int64(1), n)
+	})
+
+	t.Run("TTL", func() {
+		c, _ := t.SetupRedis(WithNegativeTTL(time.Minute))
+		t.ErrorIs(c.GetOrLoad(ctx, key, &testCacheStruct{}, Options{}, notFound), ErrNotFound)
+
+		_, err := c.TTL(ctx, key)
+		t.ErrorIs(err, ErrNotFound)
+
+		t.NoError(c.Set(ctx, key, value, Options{Expiration: time.Hour}))
+		ttl, err := c.TTL(ctx, key)
+		t.NoError(err)
+		t.Equal(time.Hour, ttl)
+	})
+}
+
+func TestIsTombstone(t *testing.T) {
+	assert.True(t, isTombstone(tombstone))
+	assert.False(t, isTombstone([]byte("value")))
+	assert.ErrorIs(t, errTombstone, ErrNotFound)
+	assert.ErrorIs(t, errTombstone, redis.Nil)
+}
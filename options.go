@@ -0,0 +1,212 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"errors"
+	"time"
+)
+
+type (
+	// Option is a functional option used to configure the
+	// Cache when calling New().
+	Option func(*config)
+	// Logger defines the methods for logging errors that
+	// are not returned to the caller, such as failures
+	// when setting tags. *log.Logger satisfies this
+	// interface.
+	Logger interface {
+		Printf(format string, v ...any)
+	}
+	// Metrics defines the methods for recording the outcome
+	// of cache operations.
+	Metrics interface {
+		// Hit is called when a key is found in the cache.
+		Hit(key string)
+		// Miss is called when a key does not exist in
+		// the cache.
+		Miss(key string)
+		// Error is called when an operation fails, op is
+		// the name of the operation such as "get".
+		Error(op, key string, err error)
+	}
+	// config represents the configurable behaviour of the
+	// Cache, obtained from the options passed to New().
+	config struct {
+		prefix         string
+		expiration     time.Duration
+		tags           []string
+		logger         Logger
+		metrics        Metrics
+		hooks          []Hook
+		stats          *stats
+		tracing        *tracingHook
+		breaker        *breaker
+		retry          *retrier
+		readTimeout    time.Duration
+		writeTimeout   time.Duration
+		negativeTTL    time.Duration
+		updateAttempts int
+		sliding        bool
+		tagExpiration  time.Duration
+		maxKeyLength   int
+		maxValueSize   int
+		chunkSize      int
+		errs           []error
+	}
+)
+
+const (
+	// DefaultTagExpiration is the default expiration time of
+	// tag sets if none is passed via WithTagExpiration.
+	DefaultTagExpiration = 720 * time.Hour
+	// prefixSeparator is the separator used between the
+	// prefix and a key or tag.
+	prefixSeparator = ":"
+)
+
+// WithPrefix prepends the prefix to every key and tag
+// stored in Redis, separated by a colon.
+func WithPrefix(prefix string) Option {
+	return func(c *config) {
+		if prefix == "" {
+			c.errs = append(c.errs, errors.New("redigo: prefix cannot be empty"))
+			return
+		}
+		c.prefix = prefix + prefixSeparator
+	}
+}
+
+// WithDefaultExpiration sets the expiration time used when
+// Options.Expiration is zero.
+func WithDefaultExpiration(exp time.Duration) Option {
+	return func(c *config) {
+		if exp < 0 {
+			c.errs = append(c.errs, errors.New("redigo: default expiration cannot be negative"))
+			return
+		}
+		c.expiration = exp
+	}
+}
+
+// WithDefaultTags sets tags that will be associated with
+// every value stored using Set(), in addition to
+// Options.Tags.
+func WithDefaultTags(tags ...string) Option {
+	return func(c *config) {
+		for _, tag := range tags {
+			if tag == "" {
+				c.errs = append(c.errs, errors.New("redigo: default tags cannot be empty"))
+				return
+			}
+		}
+		c.tags = append(c.tags, tags...)
+	}
+}
+
+// WithLogger sets the logger used for reporting errors
+// that are not returned to the caller.
+func WithLogger(logger Logger) Option {
+	return func(c *config) {
+		if logger == nil {
+			c.errs = append(c.errs, errors.New("redigo: logger cannot be nil"))
+			return
+		}
+		c.logger = logger
+	}
+}
+
+// WithMetrics sets the Metrics used for recording hits,
+// misses and errors.
+func WithMetrics(metrics Metrics) Option {
+	return func(c *config) {
+		if metrics == nil {
+			c.errs = append(c.errs, errors.New("redigo: metrics cannot be nil"))
+			return
+		}
+		c.metrics = metrics
+	}
+}
+
+// WithTagExpiration sets the expiration time of tag sets,
+// which is refreshed every time a value is tagged.
+// Defaults to DefaultTagExpiration.
+func WithTagExpiration(exp time.Duration) Option {
+	return func(c *config) {
+		if exp <= 0 {
+			c.errs = append(c.errs, errors.New("redigo: tag expiration must be positive"))
+			return
+		}
+		c.tagExpiration = exp
+	}
+}
+
+// WithMaxKeyLength limits the length of keys (excluding
+// the prefix) that can be used, zero means no limit.
+func WithMaxKeyLength(length int) Option {
+	return func(c *config) {
+		if length < 0 {
+			c.errs = append(c.errs, errors.New("redigo: max key length cannot be negative"))
+			return
+		}
+		c.maxKeyLength = length
+	}
+}
+
+// WithMaxValueSize limits the size in bytes of encoded
+// values that can be stored, zero means no limit. A
+// ValueTooLargeError is returned for larger values.
+func WithMaxValueSize(size int) Option {
+	return func(c *config) {
+		if size < 0 {
+			c.errs = append(c.errs, errors.New("redigo: max value size cannot be negative"))
+			return
+		}
+		c.maxValueSize = size
+	}
+}
+
+// newConfig applies the options to the default config
+// and validates the result.
+func newConfig(enc Encoder, options ...Option) (config, error) {
+	cfg := config{
+		logger:         nopLogger{},
+		metrics:        nopMetrics{},
+		stats:          newStats(),
+		tracing:        newTracingHook(),
+		tagExpiration:  DefaultTagExpiration,
+		updateAttempts: DefaultUpdateAttempts,
+	}
+	if enc == nil {
+		return cfg, errors.New("redigo: encoder cannot be nil")
+	}
+	for _, opt := range options {
+		if opt == nil {
+			continue
+		}
+		opt(&cfg)
+	}
+	if len(cfg.errs) > 0 {
+		return cfg, cfg.errs[0]
+	}
+	// Tracing is always the outermost hook so spans
+	// cover any hooks registered by the user.
+	cfg.hooks = append([]Hook{cfg.tracing}, cfg.hooks...)
+	return cfg, nil
+}
+
+// nopLogger is the default Logger which discards
+// all messages.
+type nopLogger struct{}
+
+func (nopLogger) Printf(string, ...any) {}
+
+// nopMetrics is the default Metrics which discards
+// all measurements.
+type nopMetrics struct{}
+
+func (nopMetrics) Hit(string)                  {}
+func (nopMetrics) Miss(string)                 {}
+func (nopMetrics) Error(string, string, error) {}
@@ -0,0 +1,199 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"context"
+	"github.com/ainsleyclark/redigo/mocks"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"log"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewConfig(t *testing.T) {
+	tt := map[string]struct {
+		input []Option
+		want  any
+	}{
+		"Defaults": {
+			nil,
+			config{
+				logger:         nopLogger{},
+				metrics:        nopMetrics{},
+				tagExpiration:  DefaultTagExpiration,
+				updateAttempts: DefaultUpdateAttempts,
+			},
+		},
+		"Nil Option": {
+			[]Option{nil},
+			config{
+				logger:         nopLogger{},
+				metrics:        nopMetrics{},
+				tagExpiration:  DefaultTagExpiration,
+				updateAttempts: DefaultUpdateAttempts,
+			},
+		},
+		"All": {
+			[]Option{
+				WithPrefix("prefix"),
+				WithDefaultExpiration(time.Hour),
+				WithDefaultTags("one", "two"),
+				WithLogger(log.Default()),
+				WithMetrics(nopMetrics{}),
+				WithTagExpiration(time.Minute),
+				WithMaxKeyLength(10),
+				WithMaxValueSize(100),
+				WithChunkSize(50),
+			},
+			config{
+				prefix:         "prefix:",
+				expiration:     time.Hour,
+				tags:           []string{"one", "two"},
+				logger:         log.Default(),
+				metrics:        nopMetrics{},
+				tagExpiration:  time.Minute,
+				maxKeyLength:   10,
+				maxValueSize:   100,
+				chunkSize:      50,
+				updateAttempts: DefaultUpdateAttempts,
+			},
+		},
+		"Empty Prefix": {
+			[]Option{WithPrefix("")},
+			"prefix cannot be empty",
+		},
+		"Negative Expiration": {
+			[]Option{WithDefaultExpiration(-1)},
+			"default expiration cannot be negative",
+		},
+		"Empty Tag": {
+			[]Option{WithDefaultTags("tag", "")},
+			"default tags cannot be empty",
+		},
+		"Nil Logger": {
+			[]Option{WithLogger(nil)},
+			"logger cannot be nil",
+		},
+		"Nil Metrics": {
+			[]Option{WithMetrics(nil)},
+			"metrics cannot be nil",
+		},
+		"Nil Hook": {
+			[]Option{WithHooks(nil)},
+			"hook cannot be nil",
+		},
+		"Nil Key Group": {
+			[]Option{WithKeyGroup(nil)},
+			"key group function cannot be nil",
+		},
+		"Nil Tracer Provider": {
+			[]Option{WithTracerProvider(nil)},
+			"tracer provider cannot be nil",
+		},
+		"Invalid Breaker Threshold": {
+			[]Option{WithCircuitBreaker(BreakerOptions{Cooldown: time.Second})},
+			"breaker threshold must be positive",
+		},
+		"Invalid Breaker Cooldown": {
+			[]Option{WithCircuitBreaker(BreakerOptions{Threshold: 1})},
+			"breaker cooldown must be positive",
+		},
+		"Invalid Retry Attempts": {
+			[]Option{WithRetry(RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: time.Second})},
+			"retry max attempts must be at least one",
+		},
+		"Invalid Retry Backoff": {
+			[]Option{WithRetry(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Second, MaxBackoff: time.Millisecond})},
+			"retry backoff must be positive",
+		},
+		"Zero Read Timeout": {
+			[]Option{WithReadTimeout(0)},
+			"read timeout must be positive",
+		},
+		"Zero Write Timeout": {
+			[]Option{WithWriteTimeout(0)},
+			"write timeout must be positive",
+		},
+		"Zero Negative TTL": {
+			[]Option{WithNegativeTTL(0)},
+			"negative ttl must be positive",
+		},
+		"Invalid Update Attempts": {
+			[]Option{WithUpdateAttempts(0)},
+			"update attempts must be positive",
+		},
+		"Zero Tag Expiration": {
+			[]Option{WithTagExpiration(0)},
+			"tag expiration must be positive",
+		},
+		"Negative Max Key Length": {
+			[]Option{WithMaxKeyLength(-1)},
+			"max key length cannot be negative",
+		},
+		"Negative Max Value Size": {
+			[]Option{WithMaxValueSize(-1)},
+			"max value size cannot be negative",
+		},
+		"Zero Chunk Size": {
+			[]Option{WithChunkSize(0)},
+			"chunk size must be positive",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got, err := newConfig(NewGobEncoder(), test.input...)
+			if err != nil {
+				assert.Contains(t, err.Error(), test.want)
+				return
+			}
+			assert.NotNil(t, got.stats)
+			assert.Equal(t, []Hook{got.tracing}, got.hooks)
+			got.stats, got.tracing, got.hooks = nil, nil, nil
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestOptions_Apply(t *testing.T) {
+	ctx := context.TODO()
+
+	cfg, err := newConfig(NewGobEncoder(),
+		WithPrefix("prefix"),
+		WithDefaultExpiration(time.Hour),
+		WithDefaultTags("default"),
+		WithTagExpiration(time.Minute),
+		WithMaxKeyLength(5),
+	)
+	assert.NoError(t, err)
+
+	m := &mocks.RedisStore{}
+	m.On("Set", mock.Anything, "prefix:key", mock.Anything, time.Hour).
+		Return(redis.NewStatusCmd(ctx, nil))
+	m.On("SAdd", mock.Anything, "prefix:default", "prefix:key").
+		Return(redis.NewIntCmd(ctx, nil))
+	m.On("SAdd", mock.Anything, "prefix:tag", "prefix:key").
+		Return(redis.NewIntCmd(ctx, nil))
+	m.On("Expire", mock.Anything, mock.Anything, time.Minute).
+		Return(redis.NewBoolCmd(ctx, true))
+
+	c := &Cache{
+		client:  m,
+		mtx:     &sync.Mutex{},
+		encoder: NewGobEncoder(),
+		cfg:     cfg,
+	}
+
+	err = c.Set(ctx, "key", "value", Options{Tags: []string{"tag"}})
+	assert.NoError(t, err)
+	m.AssertExpectations(t)
+
+	err = c.Set(ctx, "long-key", "value", Options{})
+	assert.ErrorContains(t, err, "key exceeds max length of 5")
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package prometheus exposes the stats of one or more
+// RediGo caches as Prometheus metrics.
+package prometheus
+
+import (
+	"github.com/ainsleyclark/redigo"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+type (
+	// Source defines the methods required for collecting
+	// stats, *redigo.Cache satisfies this interface.
+	Source interface {
+		Namespace() string
+		GroupStats() map[string]redigo.Stats
+	}
+	// Collector implements prometheus.Collector for the
+	// sources passed to NewCollector. Every metric is
+	// labelled by the namespace of the cache and the
+	// key group.
+	Collector struct {
+		sources []Source
+		descs   []metric
+	}
+	// metric describes a single metric and the function
+	// to obtain its value from the stats.
+	metric struct {
+		desc  *prom.Desc
+		value func(s redigo.Stats) float64
+	}
+)
+
+// labels are the variable labels attached to every metric.
+var labels = []string{"namespace", "group"}
+
+// NewCollector creates a collector for the caches passed,
+// which should be registered with prometheus.MustRegister.
+func NewCollector(sources ...Source) *Collector {
+	return &Collector{
+		sources: sources,
+		descs: []metric{
+			newMetric("hits_total", "Number of lookups that found the key.", func(s redigo.Stats) float64 {
+				return float64(s.Hits)
+			}),
+			newMetric("misses_total", "Number of lookups for keys that did not exist.", func(s redigo.Stats) float64 {
+				return float64(s.Misses)
+			}),
+			newMetric("errors_total", "Number of failed operations.", func(s redigo.Stats) float64 {
+				return float64(s.Errors)
+			}),
+			newMetric("encodes_total", "Number of values encoded.", func(s redigo.Stats) float64 {
+				return float64(s.Encodes)
+			}),
+			newMetric("encode_seconds_total", "Total time spent encoding values.", func(s redigo.Stats) float64 {
+				return s.EncodeDuration.Seconds()
+			}),
+			newMetric("decodes_total", "Number of values decoded.", func(s redigo.Stats) float64 {
+				return float64(s.Decodes)
+			}),
+			newMetric("decode_seconds_total", "Total time spent decoding values.", func(s redigo.Stats) float64 {
+				return s.DecodeDuration.Seconds()
+			}),
+			newMetric("read_bytes_total", "Total size of encoded values read.", func(s redigo.Stats) float64 {
+				return float64(s.BytesRead)
+			}),
+			newMetric("written_bytes_total", "Total size of encoded values written.", func(s redigo.Stats) float64 {
+				return float64(s.BytesWritten)
+			}),
+			newMetric("invalidated_keys_total", "Number of keys removed by tag invalidation.", func(s redigo.Stats) float64 {
+				return float64(s.InvalidatedKeys)
+			}),
+		},
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prom.Desc) {
+	for _, m := range c.descs {
+		ch <- m.desc
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prom.Metric) {
+	for _, source := range c.sources {
+		namespace := source.Namespace()
+		for group, stats := range source.GroupStats() {
+			for _, m := range c.descs {
+				ch <- prom.MustNewConstMetric(m.desc, prom.CounterValue, m.value(stats), namespace, group)
+			}
+		}
+	}
+}
+
+// newMetric creates a metric with the redigo namespace.
+func newMetric(name, help string, value func(s redigo.Stats) float64) metric {
+	return metric{
+		desc:  prom.NewDesc(prom.BuildFQName("redigo", "", name), help, labels, nil),
+		value: value,
+	}
+}
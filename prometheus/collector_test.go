@@ -0,0 +1,59 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package prometheus
+
+import (
+	"github.com/ainsleyclark/redigo"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+// source is a stub Source used for testing.
+type source struct {
+	namespace string
+	groups    map[string]redigo.Stats
+}
+
+func (s source) Namespace() string {
+	return s.namespace
+}
+
+func (s source) GroupStats() map[string]redigo.Stats {
+	return s.groups
+}
+
+func TestCollector(t *testing.T) {
+	c := NewCollector(
+		source{"app", map[string]redigo.Stats{
+			"user": {Hits: 3, Misses: 1, DecodeDuration: time.Second / 2},
+		}},
+		source{"other", map[string]redigo.Stats{
+			"": {Errors: 2},
+		}},
+	)
+
+	assert.Equal(t, 20, testutil.CollectAndCount(c))
+
+	want := `
+# HELP redigo_hits_total Number of lookups that found the key.
+# TYPE redigo_hits_total counter
+redigo_hits_total{group="",namespace="other"} 0
+redigo_hits_total{group="user",namespace="app"} 3
+# HELP redigo_decode_seconds_total Total time spent decoding values.
+# TYPE redigo_decode_seconds_total counter
+redigo_decode_seconds_total{group="",namespace="other"} 0
+redigo_decode_seconds_total{group="user",namespace="app"} 0.5
+# HELP redigo_errors_total Number of failed operations.
+# TYPE redigo_errors_total counter
+redigo_errors_total{group="",namespace="other"} 2
+redigo_errors_total{group="user",namespace="app"} 0
+`
+	err := testutil.CollectAndCompare(c, strings.NewReader(want),
+		"redigo_hits_total", "redigo_decode_seconds_total", "redigo_errors_total")
+	assert.NoError(t, err)
+}
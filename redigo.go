@@ -5,10 +5,14 @@
 package redigo
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"github.com/ainsleyclark/redigo/internal"
 	"github.com/go-redis/redis/v8"
 	"io"
+	"strings"
 	"sync"
 	"time"
 )
@@ -20,16 +24,32 @@ type (
 		client  internal.RedisStore
 		mtx     *sync.Mutex
 		encoder Encoder
+		cfg     config
 	}
 	// Options represents the cache store available options
 	// when using Set().
 	Options struct {
 		// Expiration allows to specify a global expiration
-		// time hen setting a value.
+		// time hen setting a value. If zero, the default
+		// expiration set via WithDefaultExpiration is used.
 		Expiration time.Duration
+		// ExpireAt sets an absolute time at which the value
+		// expires, such as the end of the day, instead of
+		// Expiration. It is precise to the second.
+		ExpireAt time.Time
+		// KeepTTL retains the existing expiration of the key
+		// when overwriting it, rather than applying
+		// Expiration. Values that did not exist are stored
+		// without an expiration.
+		KeepTTL bool
 		// Tags allows specifying associated tags to the
-		// current value.
+		// current value, in addition to any default tags
+		// set via WithDefaultTags.
 		Tags []string
+		// Timeout overrides the default write timeout set
+		// via WithWriteTimeout for this call. If the context
+		// has a sooner deadline, it is kept.
+		Timeout time.Duration
 	}
 	// Store defines methods for interacting with the
 	// caching system.
@@ -37,15 +57,45 @@ type (
 		// Ping pings the Redis cache to ensure its alive.
 		Ping(context.Context) error
 		// Get retrieves a specific item from the cache by key. Values are
-		// automatically marshalled for use with Redis.
+		// automatically marshalled for use with Redis. ErrNotFound is
+		// returned if the key does not exist.
 		Get(context.Context, string, any) error
 		// Set stores a singular item in memory by key, value
 		// and options (tags and expiration time). Values are automatically
 		// marshalled for use with Redis & Memcache.
 		Set(context.Context, string, any, Options) error
+		// Add stores an item only if the key does not already
+		// exist, reporting whether it was stored.
+		Add(context.Context, string, any, Options) (bool, error)
+		// Replace stores an item only if the key already
+		// exists, reporting whether it was stored.
+		Replace(context.Context, string, any, Options) (bool, error)
+		// Incr increments the integer value of a key by one.
+		Incr(context.Context, string, Options) (int64, error)
+		// IncrBy increments the integer value of a key by
+		// the amount passed.
+		IncrBy(context.Context, string, int64, Options) (int64, error)
+		// IncrByFloat increments the float value of a key by
+		// the amount passed.
+		IncrByFloat(context.Context, string, float64, Options) (float64, error)
+		// Decr decrements the integer value of a key by one.
+		Decr(context.Context, string, Options) (int64, error)
 		// Delete removes a singular item from the cache by
 		// a specific key.
 		Delete(context.Context, string) error
+		// Exists returns the number of keys passed that exist
+		// in the cache.
+		Exists(context.Context, ...string) (int64, error)
+		// TTL returns the remaining time to live of a key,
+		// NoExpiration if it has none or ErrNotFound if the key
+		// does not exist or holds a tombstone.
+		TTL(context.Context, string) (time.Duration, error)
+		// Touch sets the expiration of a key without rewriting
+		// its value. The expiration must be positive, use
+		// Persist to remove it or Delete to remove the key.
+		Touch(context.Context, string, time.Duration) error
+		// Persist removes the expiration of a key.
+		Persist(context.Context, string) error
 		// Invalidate removes items from the cache via the tags passed.
 		Invalidate(context.Context, []string)
 		// Flush removes all items from the cache.
@@ -55,18 +105,32 @@ type (
 	}
 )
 
-// New creates a new store to Redis instance(s).
-func New(opts *redis.Options, enc Encoder) *Cache {
+// NoExpiration is returned by TTL for keys without
+// an expiration.
+const NoExpiration time.Duration = -1
+
+// New creates a new store to Redis instance(s). Options
+// are applied in order and validated, an error will be
+// returned if the configuration is invalid.
+func New(opts *redis.Options, enc Encoder, options ...Option) (*Cache, error) {
+	if opts == nil {
+		return nil, errors.New("redigo: redis options cannot be nil")
+	}
+	cfg, err := newConfig(enc, options...)
+	if err != nil {
+		return nil, err
+	}
 	return &Cache{
 		client:  redis.NewClient(opts),
 		mtx:     &sync.Mutex{},
 		encoder: enc,
-	}
+		cfg:     cfg,
+	}, nil
 }
 
 // Ping pings the Redis cache to ensure its alive.
 func (c *Cache) Ping(ctx context.Context) error {
-	return c.client.Ping(ctx).Err()
+	return c.ping(ctx)
 }
 
 // Close closes the client, releasing any open resources.
@@ -75,60 +139,238 @@ func (c *Cache) Close() error {
 }
 
 // Get retrieves a specific item from the cache by key. Values are
-// automatically marshalled for use with Redis.
+// automatically marshalled for use with Redis. ErrNotFound is
+// returned if the key does not exist and a DecodeError if the
+// value could not be decoded.
 func (c *Cache) Get(ctx context.Context, key string, v any) error {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
-
-	result, err := c.client.Get(ctx, key).Result()
-	if err != nil {
-		return err
-	}
-
-	err = c.encoder.Decode([]byte(result), v)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return c.get(ctx, key, v)
 }
 
 // Set stores a singular item in memory by key, value
 // and options (tags and expiration time). Values are automatically
-// marshalled for use with Redis & Memcache.
+// marshalled for use with Redis & Memcache. An EncodeError is
+// returned if the value could not be encoded.
 func (c *Cache) Set(ctx context.Context, key string, value any, options Options) error {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
+	_, _, err := c.set(ctx, OpSet, key, value, options)
+	return err
+}
 
-	buf, err := c.encoder.Encode(value)
-	if err != nil {
-		return err
+// Add stores an item only if the key does not already exist,
+// such as for idempotency keys or where the first writer
+// wins. It reports whether the value was stored, tags and
+// expiration are only applied if it was. A tombstone stored
+// by GetOrLoad counts as a missing key and is replaced.
+func (c *Cache) Add(ctx context.Context, key string, value any, options Options) (bool, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	_, ok, err := c.set(ctx, OpAdd, key, value, options)
+	return ok, err
+}
+
+// Replace stores an item only if the key already exists. It
+// reports whether the value was stored, tags and expiration
+// are only applied if it was. A tombstone stored by
+// GetOrLoad counts as a missing key and is left in place.
+func (c *Cache) Replace(ctx context.Context, key string, value any, options Options) (bool, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	_, ok, err := c.set(ctx, OpReplace, key, value, options)
+	return ok, err
+}
+
+// Delete removes a singular item from the cache by
+// a specific key.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.do(ctx, &Event{Operation: OpDelete, Key: key}, func(ctx context.Context, e *Event) error {
+		k, err := c.key(key)
+		if err != nil {
+			return err
+		}
+		m, err := c.loadManifest(ctx, k)
+		if err != nil {
+			return err
+		}
+		return c.retry(ctx, func() error {
+			return c.client.Del(ctx, append(c.slidingKeys(k), m.keys(k)...)...).Err()
+		})
+	})
+}
+
+// Exists returns the number of keys passed that exist
+// in the cache. Tombstones stored by GetOrLoad are not
+// counted.
+func (c *Cache) Exists(ctx context.Context, keys ...string) (int64, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if len(keys) == 0 {
+		return 0, nil
 	}
 
-	err = c.client.Set(ctx, key, buf, options.Expiration).Err()
-	if err != nil {
-		return err
+	e := &Event{Operation: OpExists}
+	if len(keys) == 1 {
+		e.Key = keys[0]
 	}
 
-	if len(options.Tags) > 0 {
-		c.setTags(ctx, key, options.Tags)
+	var n int64
+	err := c.do(ctx, e, func(ctx context.Context, e *Event) error {
+		ks := make([]string, len(keys))
+		for i, key := range keys {
+			k, err := c.key(key)
+			if err != nil {
+				return err
+			}
+			ks[i] = k
+		}
+		return c.retry(ctx, func() (err error) {
+			n, err = existsScript.Run(ctx, c.client, ks, tombstone).Int64()
+			return err
+		})
+	})
+	if errors.Is(err, ErrNotFound) {
+		return 0, nil
 	}
 
-	return nil
+	return n, err
 }
 
-// Delete removes a singular item from the cache by
-// a specific key.
-func (c *Cache) Delete(ctx context.Context, key string) error {
+// TTL returns the remaining time to live of a key,
+// NoExpiration if it has none or ErrNotFound if the key
+// does not exist or holds a tombstone stored by GetOrLoad.
+func (c *Cache) TTL(ctx context.Context, key string) (time.Duration, error) {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
 
-	_, err := c.client.Del(ctx, key).Result()
-	if err != nil {
-		return err
+	var ttl time.Duration
+	err := c.do(ctx, &Event{Operation: OpTTL, Key: key}, func(ctx context.Context, e *Event) error {
+		k, err := c.key(key)
+		if err != nil {
+			return err
+		}
+		var ms int64
+		err = c.retry(ctx, func() (err error) {
+			ms, err = ttlScript.Run(ctx, c.client, []string{k}, tombstone).Int64()
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		// ttlScript replies with -2 if the key does not
+		// exist and -1 if it has no expiration.
+		switch ms {
+		case -2:
+			return ErrNotFound
+		case -1:
+			ttl = NoExpiration
+		default:
+			ttl = time.Duration(ms) * time.Millisecond
+		}
+		e.Hit = true
+		return nil
+	})
+
+	return ttl, err
+}
+
+// Touch sets the expiration of a key without rewriting
+// its value. With sliding expiration, the key slides by
+// the new expiration from then on. ErrNotFound is
+// returned if the key does not exist, and an error if the
+// expiration is not positive, as Redis would remove the
+// key rather than extend it.
+func (c *Cache) Touch(ctx context.Context, key string, exp time.Duration) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if exp <= 0 {
+		return errors.New("redigo: touch expiration must be positive")
 	}
 
-	return nil
+	return c.do(ctx, &Event{Operation: OpTouch, Key: key}, func(ctx context.Context, e *Event) error {
+		k, err := c.key(key)
+		if err != nil {
+			return err
+		}
+		m, err := c.loadManifest(ctx, k)
+		if err != nil {
+			return err
+		}
+		var ok bool
+		err = c.retry(ctx, func() (err error) {
+			ok, err = c.client.Expire(ctx, k, exp).Result()
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrNotFound
+		}
+		err = c.pipeChunks(ctx, m.keys(k), func(pipe redis.Pipeliner, _ int, key string) {
+			pipe.Expire(ctx, key, exp)
+		})
+		if err != nil {
+			return err
+		}
+		return c.touchSliding(ctx, k, m, exp)
+	})
+}
+
+// Persist removes the expiration of a key, including any
+// sliding expiration. ErrNotFound is returned if the key
+// does not exist.
+func (c *Cache) Persist(ctx context.Context, key string) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.do(ctx, &Event{Operation: OpPersist, Key: key}, func(ctx context.Context, e *Event) error {
+		k, err := c.key(key)
+		if err != nil {
+			return err
+		}
+		m, err := c.loadManifest(ctx, k)
+		if err != nil {
+			return err
+		}
+		err = c.pipeChunks(ctx, m.keys(k), func(pipe redis.Pipeliner, _ int, key string) {
+			pipe.Persist(ctx, key)
+		})
+		if err != nil {
+			return err
+		}
+		var ok bool
+		err = c.retry(ctx, func() (err error) {
+			ok, err = c.client.Persist(ctx, k).Result()
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if ok {
+			return c.touchSliding(ctx, k, m, 0)
+		}
+		// Redis replies with false if the key does not exist
+		// or if it has no expiration, which is not an error.
+		var n int64
+		err = c.retry(ctx, func() (err error) {
+			n, err = c.client.Exists(ctx, k).Result()
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
 }
 
 // Invalidate removes items from the cache from the tags passed.
@@ -140,31 +382,383 @@ func (c *Cache) Invalidate(ctx context.Context, tags []string) {
 		return
 	}
 
-	for _, tag := range tags {
-		cacheKeys, err := c.client.SMembers(ctx, tag).Result()
-		if err != nil {
-			continue
-		}
+	_ = c.do(ctx, &Event{Operation: OpInvalidate, Tags: tags}, func(ctx context.Context, e *Event) error {
+		var lastErr error
+		for _, tag := range tags {
+			tag = c.cfg.prefix + tag
+			var cacheKeys []string
+			err := c.retry(ctx, func() (err error) {
+				cacheKeys, err = c.client.SMembers(ctx, tag).Result()
+				return err
+			})
+			if err != nil {
+				c.cfg.logger.Printf("redigo: error obtaining members of tag %s: %s", tag, err.Error())
+				lastErr = err
+				continue
+			}
 
-		for _, cacheKey := range cacheKeys {
-			c.client.Del(ctx, cacheKey)
-		}
+			for _, cacheKey := range cacheKeys {
+				err := c.retry(ctx, func() error {
+					return c.client.Del(ctx, c.slidingKeys(cacheKey)...).Err()
+				})
+				if err != nil {
+					continue
+				}
+				c.cfg.stats.record(strings.TrimPrefix(cacheKey, c.cfg.prefix), func(s *Stats) {
+					s.InvalidatedKeys++
+				})
+			}
 
-		c.client.Del(ctx, tag)
-	}
+			_ = c.retry(ctx, func() error {
+				return c.client.Del(ctx, tag).Err()
+			})
+		}
+		return lastErr
+	})
 }
 
 // Flush removes all items from the cache.
 func (c *Cache) Flush(ctx context.Context) {
 	c.mtx.Lock()
 	defer c.mtx.Unlock()
-	c.client.FlushAll(ctx)
+
+	_ = c.do(ctx, &Event{Operation: OpFlush}, func(ctx context.Context, e *Event) error {
+		return c.client.FlushAll(ctx).Err()
+	})
 }
 
-// setTags sets SMembers in the redis store for caching.
-func (c *Cache) setTags(ctx context.Context, key any, tags []string) {
+// get retrieves and decodes the value of the key into v
+// without acquiring the lock.
+func (c *Cache) get(ctx context.Context, key string, v any) error {
+	return c.do(ctx, &Event{Operation: OpGet, Key: key}, func(ctx context.Context, e *Event) error {
+		k, err := c.key(key)
+		if err != nil {
+			return err
+		}
+
+		var buf []byte
+		err = c.retry(ctx, func() error {
+			buf, err = c.fetch(ctx, k)
+			return err
+		})
+		if errors.Is(err, redis.Nil) {
+			return ErrNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if isTombstone(buf) {
+			return errTombstone
+		}
+
+		if m, ok := parseManifest(buf); ok {
+			buf, err = c.readChunks(ctx, k, m)
+			if err != nil {
+				return err
+			}
+		}
+
+		e.Hit = true
+		e.Size = len(buf)
+
+		return c.decode(ctx, key, buf, v)
+	})
+}
+
+// set encodes and stores the value without acquiring the
+// lock, the encoded value is returned along with whether it
+// was stored, which is only false for Add and Replace.
+func (c *Cache) set(ctx context.Context, op Operation, key string, value any, options Options) ([]byte, bool, error) {
+	err := options.validate()
+	if err != nil {
+		return nil, false, err
+	}
+
+	ctx, cancel := timeoutOverride(ctx, options.Timeout)
+	defer cancel()
+
+	var (
+		buf []byte
+		ok  bool
+	)
+	e := &Event{Operation: op, Key: key, Tags: c.tags(options.Tags)}
+
+	err = c.do(ctx, e, func(ctx context.Context, e *Event) error {
+		k, err := c.key(key)
+		if err != nil {
+			return err
+		}
+
+		buf, err = c.encode(ctx, key, value)
+		if err != nil {
+			return err
+		}
+		e.Size = len(buf)
+
+		ok, err = c.write(ctx, k, buf, setModes[op], options, e.Tags)
+		if err != nil {
+			return err
+		}
+
+		// The key existed if an add was rejected or
+		// a replace succeeded.
+		if op != OpSet {
+			e.Hit = ok == (op == OpReplace)
+		}
+
+		return nil
+	})
+
+	return buf, ok, err
+}
+
+// write stores the buffer under the prefixed key and sets
+// its tags. If no expiration is set in the options, the
+// default expiration is used. The mode is either empty, NX
+// or XX, it reports whether the buffer was stored. Buffers
+// exceeding the chunk size are stored across chunk keys.
+func (c *Cache) write(ctx context.Context, key string, buf []byte, mode string, options Options, tags []string) (bool, error) {
+	exp := c.expiration(options)
+
+	buf, w, err := c.chunk(ctx, key, buf, exp, options)
+	if err != nil {
+		return false, err
+	}
+
+	return c.store(ctx, key, buf, w, mode, exp, options, tags)
+}
+
+// store stores the buffer, or the manifest of the chunks
+// written, under the prefixed key with the expiration from
+// Cache.expiration. Tags are set on the key and its chunks
+// once stored, and stale chunks are removed. The chunks
+// written are removed if the buffer was not stored.
+func (c *Cache) store(ctx context.Context, key string, buf []byte, w chunkWrite, mode string, exp time.Duration, options Options, tags []string) (bool, error) {
+	var err error
+	if mode != "" {
+		// Conditional writes are not retried, as a retry
+		// of an applied write would fail its condition.
+		err = condSetScript.Run(ctx, c.client, []string{key}, condSetArgs(buf, mode, exp, options)...).Err()
+	} else {
+		err = c.retry(ctx, func() error {
+			if options.ExpireAt.IsZero() {
+				return c.client.Set(ctx, key, buf, exp).Err()
+			}
+			return c.client.SetArgs(ctx, key, buf, setArgs(exp, options)).Err()
+		})
+	}
+	if errors.Is(err, redis.Nil) {
+		// condSetScript replies with nil when the
+		// condition was not met.
+		c.deleteChunks(ctx, w.keys)
+		return false, nil
+	} else if err != nil {
+		c.deleteChunks(ctx, w.keys)
+		return false, err
+	}
+
+	err = c.writeSliding(ctx, key, exp, tags)
+	if err != nil {
+		return false, err
+	}
+
+	c.setTags(ctx, tags, append([]string{key}, w.keys...)...)
+	c.deleteChunks(ctx, w.stale)
+
+	return true, nil
+}
+
+// expiration returns the expiration to store a value with,
+// redis.KeepTTL for KeepTTL or zero for ExpireAt. If no
+// expiration is set, the default expiration is used.
+func (c *Cache) expiration(options Options) time.Duration {
+	switch {
+	case options.KeepTTL:
+		return redis.KeepTTL
+	case !options.ExpireAt.IsZero():
+		// Absolute expirations do not slide, so any
+		// previous sliding metadata is removed.
+		return 0
+	case options.Expiration == 0:
+		return c.cfg.expiration
+	}
+	return options.Expiration
+}
+
+// setArgs returns the arguments for SET with the expiration
+// obtained from Cache.expiration.
+func setArgs(exp time.Duration, options Options) redis.SetArgs {
+	args := redis.SetArgs{ExpireAt: options.ExpireAt}
+	if exp == redis.KeepTTL {
+		args.KeepTTL = true
+	} else if exp > 0 {
+		args.TTL = exp
+	}
+	return args
+}
+
+// setModes maps conditional write operations to the mode
+// passed to condSetScript.
+var setModes = map[Operation]string{
+	OpAdd:     "NX",
+	OpReplace: "XX",
+}
+
+// condSetScript sets KEYS[1] to ARGV[1] if the condition
+// passed as ARGV[2] is met, NX if the key does not exist or
+// XX if it does. A tombstone, passed as ARGV[3], counts as a
+// missing key and is removed before the value is set. The
+// remaining arguments are passed to SET. Nil is returned if
+// the condition was not met.
+var condSetScript = redis.NewScript(tombstoneLua + `
+local exists = redis.call('EXISTS', KEYS[1]) == 1
+local tombstone = exists and is_tombstone(KEYS[1], ARGV[3])
+if tombstone then
+	exists = false
+end
+if (ARGV[2] == 'NX') == exists then
+	return false
+end
+if tombstone then
+	redis.call('DEL', KEYS[1])
+end
+redis.call('SET', KEYS[1], ARGV[1], unpack(ARGV, 4))
+return 1
+`)
+
+// condSetArgs returns the arguments for condSetScript with
+// the expiration obtained from Cache.expiration.
+func condSetArgs(buf []byte, mode string, exp time.Duration, options Options) []any {
+	args := []any{buf, mode, tombstone}
+	switch {
+	case exp == redis.KeepTTL:
+		args = append(args, "KEEPTTL")
+	case !options.ExpireAt.IsZero():
+		args = append(args, "EXAT", options.ExpireAt.Unix())
+	case exp > 0:
+		args = append(args, "PX", exp.Milliseconds())
+	}
+	return args
+}
+
+// validate checks that at most one of Expiration, ExpireAt
+// and KeepTTL is set, and that ExpireAt is in the future.
+func (o Options) validate() error {
+	n := 0
+	for _, set := range []bool{o.Expiration != 0, !o.ExpireAt.IsZero(), o.KeepTTL} {
+		if set {
+			n++
+		}
+	}
+	if n > 1 {
+		return errors.New("redigo: only one of expiration, expire at and keep ttl can be set")
+	}
+	if !o.ExpireAt.IsZero() && !o.ExpireAt.After(time.Now()) {
+		return errors.New("redigo: expire at must be in the future")
+	}
+	return nil
+}
+
+// ping pings Redis without acquiring the lock, used
+// for probing by the circuit breaker.
+func (c *Cache) ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+// key returns the key used for storage in Redis,
+// validating its length and applying the prefix.
+func (c *Cache) key(key string) (string, error) {
+	if c.cfg.maxKeyLength > 0 && len(key) > c.cfg.maxKeyLength {
+		return "", fmt.Errorf("%w of %d", ErrKeyTooLong, c.cfg.maxKeyLength)
+	}
+	return c.cfg.prefix + key, nil
+}
+
+// encode encodes the value using the Encoder, recording
+// the time taken. Values which could be mistaken for a
+// counter are escaped by escapeNumber, unless the Encoder
+// is NewRawEncoder, which stores values as is. A
+// ValueTooLargeError is returned if the encoded value
+// exceeds the size set via WithMaxValueSize.
+func (c *Cache) encode(ctx context.Context, key string, value any) ([]byte, error) {
+	_, span := c.cfg.tracing.start(ctx, "encode")
+	start := time.Now()
+	buf, err := c.encoder.Encode(value)
+	d := time.Since(start)
+	c.cfg.tracing.end(span, len(buf), err)
+	c.cfg.stats.record(key, func(s *Stats) {
+		s.Encodes++
+		s.EncodeDuration += d
+	})
+	if err != nil {
+		return nil, &EncodeError{Key: key, Err: err}
+	}
+	if _, raw := c.encoder.(*rawEnc); !raw {
+		buf = escapeNumber(buf, value)
+	}
+	if c.cfg.maxValueSize > 0 && len(buf) > c.cfg.maxValueSize {
+		return nil, &ValueTooLargeError{Key: key, Size: len(buf), Max: c.cfg.maxValueSize}
+	}
+	return buf, nil
+}
+
+// decode decodes the data into v using the Encoder,
+// recording the time taken. Plain numbers stored by the
+// counter methods are parsed directly into numeric types,
+// values escaped by escapeNumber are passed to the Encoder.
+func (c *Cache) decode(ctx context.Context, key string, data []byte, v any) error {
+	_, span := c.cfg.tracing.start(ctx, "decode")
+	start := time.Now()
+	var err error
+	if bytes.HasPrefix(data, numberEscape) {
+		err = c.encoder.Decode(data[len(numberEscape):], v)
+	} else if !decodeNumber(data, v) {
+		err = c.encoder.Decode(data, v)
+	}
+	d := time.Since(start)
+	c.cfg.tracing.end(span, len(data), err)
+	c.cfg.stats.record(key, func(s *Stats) {
+		s.Decodes++
+		s.DecodeDuration += d
+	})
+	if err != nil {
+		return &DecodeError{Key: key, Err: err}
+	}
+	return nil
+}
+
+// tags merges the default tags with the tags passed.
+func (c *Cache) tags(tags []string) []string {
+	merged := make([]string, 0, len(c.cfg.tags)+len(tags))
+	merged = append(merged, c.cfg.tags...)
+	return append(merged, tags...)
+}
+
+// setTags sets SMembers in the redis store for caching,
+// the prefix is applied to each tag. The first key is the
+// key of the value, followed by any of its chunk keys.
+func (c *Cache) setTags(ctx context.Context, tags []string, keys ...string) {
+	members := make([]any, len(keys))
+	for i, key := range keys {
+		members[i] = key
+	}
 	for _, tag := range tags {
-		c.client.SAdd(ctx, tag, key.(string))
-		c.client.Expire(ctx, tag, 720*time.Hour)
+		tag = c.cfg.prefix + tag
+		err := c.retry(ctx, func() error {
+			return c.client.SAdd(ctx, tag, members...).Err()
+		})
+		if err != nil {
+			c.cfg.logger.Printf("redigo: error adding key %s to tag %s: %s", keys[0], tag, err.Error())
+			continue
+		}
+		_ = c.retry(ctx, func() error {
+			return c.client.Expire(ctx, tag, c.cfg.tagExpiration).Err()
+		})
 	}
 }
+
+// retry calls fn in accordance with the RetryPolicy set via
+// WithRetry. It must only be used for idempotent commands.
+func (c *Cache) retry(ctx context.Context, fn func() error) error {
+	return c.cfg.retry.do(ctx, fn)
+}
@@ -5,8 +5,14 @@
 package redigo
 
 import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+	"strconv"
 	"testing"
+	"time"
 )
 
 var (
@@ -16,11 +22,95 @@ var (
 	}{
 		{"JSON", NewJSONEncoder()},
 		{"Gob", NewGobEncoder()},
+		{"Gob Stream", NewGobStreamEncoder()},
 		{"Message Pack", NewMessagePackEncoder()},
 		{"Go JSON", NewGoJSONEncoder()},
+		{"Go JSON Pooled", NewPooledGoJSONEncoder()},
+		{"Segment JSON", NewSegmentJSONEncoder()},
+		{"CBOR", NewCBOREncoder()},
+		{"CBOR Deterministic", NewDeterministicCBOREncoder()},
 	}
 )
 
+type (
+	// benchUser is a realistic payload for benchmarks,
+	// with nested structs, slices, maps and times.
+	benchUser struct {
+		ID        int64             `json:"id"`
+		Name      string            `json:"name"`
+		Email     string            `json:"email"`
+		Active    bool              `json:"active"`
+		Balance   float64           `json:"balance"`
+		CreatedAt time.Time         `json:"created_at"`
+		Roles     []string          `json:"roles"`
+		Meta      map[string]string `json:"meta"`
+		Address   benchAddress      `json:"address"`
+		Orders    []benchOrder      `json:"orders"`
+	}
+	benchAddress struct {
+		Line1    string `json:"line1"`
+		City     string `json:"city"`
+		Postcode string `json:"postcode"`
+		Country  string `json:"country"`
+	}
+	benchOrder struct {
+		ID       int64     `json:"id"`
+		SKU      string    `json:"sku"`
+		Quantity int       `json:"quantity"`
+		Price    float64   `json:"price"`
+		PlacedAt time.Time `json:"placed_at"`
+	}
+)
+
+// payloads are the values encoded and decoded by the
+// struct benchmarks, new returns a pointer to decode into.
+var payloads = []struct {
+	name  string
+	value any
+	new   func() any
+}{
+	{"User", createUser(1), func() any { return &benchUser{} }},
+	{"Users", createUsers(50), func() any { return &[]benchUser{} }},
+}
+
+func createUser(id int64) benchUser {
+	created := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	u := benchUser{
+		ID:        id,
+		Name:      "Jane Doe",
+		Email:     "jane.doe@example.com",
+		Active:    true,
+		Balance:   1024.75,
+		CreatedAt: created,
+		Roles:     []string{"admin", "editor", "viewer"},
+		Meta:      map[string]string{"locale": "en-GB", "theme": "dark", "plan": "pro"},
+		Address: benchAddress{
+			Line1:    "1 High Street",
+			City:     "London",
+			Postcode: "SW1A 1AA",
+			Country:  "GB",
+		},
+	}
+	for i := 0; i < 5; i++ {
+		u.Orders = append(u.Orders, benchOrder{
+			ID:       id*100 + int64(i),
+			SKU:      "SKU-" + strconv.Itoa(i),
+			Quantity: i + 1,
+			Price:    9.99 * float64(i+1),
+			PlacedAt: created.Add(time.Duration(i) * time.Hour),
+		})
+	}
+	return u
+}
+
+func createUsers(n int) []benchUser {
+	users := make([]benchUser, n)
+	for i := range users {
+		users[i] = createUser(int64(i))
+	}
+	return users
+}
+
 func createMap(max int) map[int64]float64 {
 	m := make(map[int64]float64)
 	for i := 0; i < max; i++ {
@@ -30,10 +120,9 @@ func createMap(max int) map[int64]float64 {
 }
 
 func BenchmarkEncode(b *testing.B) {
-	b.ReportAllocs()
-
 	for _, merge := range merges {
 		b.Run(merge.name, func(b *testing.B) {
+			b.ReportAllocs()
 			m := createMap(100)
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
@@ -49,10 +138,9 @@ func BenchmarkEncode(b *testing.B) {
 }
 
 func BenchmarkDecode(b *testing.B) {
-	b.ReportAllocs()
-
 	for _, merge := range merges {
 		b.Run(merge.name+"/", func(b *testing.B) {
+			b.ReportAllocs()
 			buf, err := merge.enc.Encode(createMap(100))
 			assert.NoError(b, err)
 			b.ResetTimer()
@@ -66,3 +154,126 @@ func BenchmarkDecode(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkEncodeStruct(b *testing.B) {
+	for _, payload := range payloads {
+		for _, merge := range merges {
+			b.Run(payload.name+"/"+merge.name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					res, err := merge.enc.Encode(payload.value)
+					_ = res
+					if err != nil {
+						b.Logf("Error during benchmark: %s", err.Error())
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkDecodeStruct(b *testing.B) {
+	for _, payload := range payloads {
+		for _, merge := range merges {
+			b.Run(payload.name+"/"+merge.name, func(b *testing.B) {
+				b.ReportAllocs()
+				buf, err := merge.enc.Encode(payload.value)
+				assert.NoError(b, err)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					err := merge.enc.Decode(buf, payload.new())
+					if err != nil {
+						b.Logf("Error during benchmark: %s", err.Error())
+					}
+				}
+			})
+		}
+	}
+}
+
+// setupBenchCache returns a cache backed by an in memory
+// Redis server using the encoder.
+func setupBenchCache(b *testing.B, enc Encoder) *Cache {
+	mr := miniredis.RunT(b)
+	c, err := New(&redis.Options{Addr: mr.Addr()}, enc)
+	assert.NoError(b, err)
+	return c
+}
+
+func BenchmarkCache_Set(b *testing.B) {
+	for _, merge := range merges {
+		b.Run(merge.name, func(b *testing.B) {
+			b.ReportAllocs()
+			c := setupBenchCache(b, merge.enc)
+			user := createUser(1)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				err := c.Set(context.Background(), "user", user, Options{})
+				if err != nil {
+					b.Logf("Error during benchmark: %s", err.Error())
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkCache_Get(b *testing.B) {
+	for _, merge := range merges {
+		b.Run(merge.name, func(b *testing.B) {
+			b.ReportAllocs()
+			c := setupBenchCache(b, merge.enc)
+			assert.NoError(b, c.Set(context.Background(), "user", createUser(1), Options{}))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var user benchUser
+				err := c.Get(context.Background(), "user", &user)
+				if err != nil {
+					b.Logf("Error during benchmark: %s", err.Error())
+				}
+			}
+		})
+	}
+}
+
+// createStruct returns a proto message equivalent to
+// the map returned by createMap.
+func createStruct(b *testing.B, max int) *structpb.Struct {
+	m := make(map[string]any)
+	for k, v := range createMap(max) {
+		m[strconv.FormatInt(k, 10)] = v
+	}
+	s, err := structpb.NewStruct(m)
+	assert.NoError(b, err)
+	return s
+}
+
+func BenchmarkEncodeProto(b *testing.B) {
+	b.ReportAllocs()
+
+	enc := NewProtoEncoder()
+	s := createStruct(b, 100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		res, err := enc.Encode(s)
+		_ = res
+		if err != nil {
+			b.Logf("Error during benchmark: %s", err.Error())
+		}
+	}
+}
+
+func BenchmarkDecodeProto(b *testing.B) {
+	b.ReportAllocs()
+
+	enc := NewProtoEncoder()
+	buf, err := enc.Encode(createStruct(b, 100))
+	assert.NoError(b, err)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s := &structpb.Struct{}
+		err := enc.Decode(buf, s)
+		if err != nil {
+			b.Logf("Error during benchmark: %s", err.Error())
+		}
+	}
+}
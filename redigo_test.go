@@ -11,7 +11,9 @@ import (
 	"errors"
 	"fmt"
 	"github.com/ainsleyclark/redigo/mocks"
+	"github.com/alicebob/miniredis/v2"
 	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"sync"
@@ -49,13 +51,26 @@ func (t *CacheTestSuite) Setup(mf func(m *mocks.RedisStore, enc *mocks.Encoder))
 	if mf != nil {
 		mf(m, e)
 	}
+	cfg, err := newConfig(e)
+	t.NoError(err)
 	return &Cache{
 		client:  m,
 		mtx:     &sync.Mutex{},
 		encoder: e,
+		cfg:     cfg,
 	}
 }
 
+// SetupRedis is a helper to obtain a cache store backed by
+// an in-memory Redis server, for testing commands that
+// cannot be mocked, such as scripts and transactions.
+func (t *CacheTestSuite) SetupRedis(opts ...Option) (*Cache, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t.T())
+	c, err := New(&redis.Options{Addr: mr.Addr()}, NewGobEncoder(), opts...)
+	t.NoError(err)
+	return c, mr
+}
+
 type (
 	// testCacheStruct represents a struct for working with
 	// JSON values within the cache store.
@@ -86,10 +101,51 @@ var (
 )
 
 func (t *CacheTestSuite) TestNew() {
-	got := New(&redis.Options{}, NewGobEncoder())
-	t.NotNil(got.client)
-	t.NotNil(got.mtx)
-	t.NotNil(got.encoder)
+	tt := map[string]struct {
+		opts    *redis.Options
+		enc     Encoder
+		options []Option
+		want    any
+	}{
+		"Success": {
+			&redis.Options{},
+			NewGobEncoder(),
+			[]Option{WithPrefix("prefix"), WithDefaultExpiration(time.Hour)},
+			nil,
+		},
+		"Nil Redis Options": {
+			nil,
+			NewGobEncoder(),
+			nil,
+			"redis options cannot be nil",
+		},
+		"Nil Encoder": {
+			&redis.Options{},
+			nil,
+			nil,
+			"encoder cannot be nil",
+		},
+		"Invalid Option": {
+			&redis.Options{},
+			NewGobEncoder(),
+			[]Option{WithTagExpiration(0)},
+			"tag expiration must be positive",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func() {
+			got, err := New(test.opts, test.enc, test.options...)
+			if err != nil {
+				t.Contains(err.Error(), test.want)
+				return
+			}
+			t.NotNil(got.client)
+			t.NotNil(got.mtx)
+			t.NotNil(got.encoder)
+			t.Equal("prefix:", got.cfg.prefix)
+		})
+	}
 }
 
 func (t *CacheTestSuite) TestPing() {
@@ -99,7 +155,7 @@ func (t *CacheTestSuite) TestPing() {
 	}{
 		"Success": {
 			func(m *mocks.RedisStore, enc *mocks.Encoder) {
-				m.On("Ping", ctx).
+				m.On("Ping", mock.Anything).
 					Return(redis.NewStatusCmd(ctx, nil))
 			},
 			nil,
@@ -108,7 +164,7 @@ func (t *CacheTestSuite) TestPing() {
 			func(m *mocks.RedisStore, enc *mocks.Encoder) {
 				cmd := redis.NewStatusCmd(ctx, nil)
 				cmd.SetErr(errors.New("ping error"))
-				m.On("Ping", ctx).
+				m.On("Ping", mock.Anything).
 					Return(cmd)
 			},
 			"ping error",
@@ -186,6 +242,13 @@ func (t *CacheTestSuite) TestCache_Get() {
 			},
 			"redis error",
 		},
+		"Not Found": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Get", mock.Anything, key).
+					Return(redis.NewStringResult("", redis.Nil))
+			},
+			ErrNotFound.Error(),
+		},
 		"Decode Error": {
 			func(m *mocks.RedisStore, enc *mocks.Encoder) {
 				m.On("Get", mock.Anything, key).
@@ -194,7 +257,7 @@ func (t *CacheTestSuite) TestCache_Get() {
 				enc.On("Decode", t.GobBuf, &testCacheStruct{}).
 					Return(fmt.Errorf("decode error"))
 			},
-			"decode error",
+			"error decoding key key: decode error",
 		},
 	}
 
@@ -229,10 +292,10 @@ func (t *CacheTestSuite) TestCache_Set() {
 				m.On("Set", mock.Anything, key, t.GobBuf, options.Expiration).
 					Return(redis.NewStatusCmd(ctx, nil))
 
-				m.On("SAdd", ctx, "tag", "key").
+				m.On("SAdd", mock.Anything, "tag", "key").
 					Return(redis.NewIntCmd(ctx, ""))
 
-				m.On("Expire", ctx, "tag", 720*time.Hour).
+				m.On("Expire", mock.Anything, "tag", 720*time.Hour).
 					Return(redis.NewBoolCmd(ctx, true))
 			},
 			false,
@@ -260,7 +323,7 @@ func (t *CacheTestSuite) TestCache_Set() {
 					Return(nil, fmt.Errorf("encode error"))
 			},
 			true,
-			"encode error",
+			"error encoding key key: encode error",
 		},
 	}
 
@@ -276,6 +339,149 @@ func (t *CacheTestSuite) TestCache_Set() {
 	}
 }
 
+func (t *CacheTestSuite) TestCache_Set_Expiry() {
+	c, mr := t.SetupRedis()
+
+	t.Run("Expire At", func() {
+		err := c.Set(ctx, key, value, Options{ExpireAt: time.Now().Add(time.Hour)})
+		t.NoError(err)
+		t.InDelta(time.Hour, mr.TTL(key), float64(time.Second))
+	})
+
+	t.Run("Keep TTL", func() {
+		err := c.Set(ctx, key, value, Options{Expiration: time.Minute})
+		t.NoError(err)
+		mr.FastForward(time.Second * 10)
+
+		err = c.Set(ctx, key, testCacheStruct{Value: 2}, Options{KeepTTL: true})
+		t.NoError(err)
+		t.Equal(time.Second*50, mr.TTL(key))
+
+		got := testCacheStruct{}
+		t.NoError(c.Get(ctx, key, &got))
+		t.Equal(2, got.Value)
+	})
+
+	t.Run("Invalid", func() {
+		err := c.Set(ctx, key, value, Options{Expiration: time.Minute, KeepTTL: true})
+		t.Error(err)
+	})
+}
+
+func (t *CacheTestSuite) TestCache_Add() {
+	c, mr := t.SetupRedis()
+	opts := Options{Expiration: time.Minute, Tags: []string{tag}}
+
+	ok, err := c.Add(ctx, key, value, opts)
+	t.NoError(err)
+	t.True(ok)
+	t.Equal(time.Minute, mr.TTL(key))
+	t.True(mr.Exists(tag))
+
+	ok, err = c.Add(ctx, key, testCacheStruct{Value: 2}, opts)
+	t.NoError(err)
+	t.False(ok)
+
+	got := testCacheStruct{}
+	t.NoError(c.Get(ctx, key, &got))
+	t.Equal(value, got)
+	t.Equal(uint64(len(t.GobBuf)), c.Stats().BytesWritten)
+
+	// Open breaker, the result is unknown.
+	c.cfg.breaker = t.openBreaker()
+	ok, err = c.Add(ctx, "other", value, opts)
+	t.ErrorIs(err, ErrUnavailable)
+	t.False(ok)
+	t.False(mr.Exists("other"))
+}
+
+func (t *CacheTestSuite) TestCache_Replace() {
+	c, mr := t.SetupRedis()
+	opts := Options{Expiration: time.Minute, Tags: []string{tag}}
+
+	ok, err := c.Replace(ctx, key, value, opts)
+	t.NoError(err)
+	t.False(ok)
+	t.False(mr.Exists(key))
+	t.False(mr.Exists(tag))
+
+	t.NoError(c.Set(ctx, key, testCacheStruct{Value: 2}, Options{}))
+	ok, err = c.Replace(ctx, key, value, opts)
+	t.NoError(err)
+	t.True(ok)
+	t.Equal(time.Minute, mr.TTL(key))
+	t.True(mr.Exists(tag))
+
+	got := testCacheStruct{}
+	t.NoError(c.Get(ctx, key, &got))
+	t.Equal(value, got)
+
+	// Open breaker, the result is unknown.
+	c.cfg.breaker = t.openBreaker()
+	ok, err = c.Replace(ctx, key, testCacheStruct{Value: 3}, opts)
+	t.ErrorIs(err, ErrUnavailable)
+	t.False(ok)
+}
+
+func (t *CacheTestSuite) TestCache_Add_Error() {
+	c := t.Setup(func(m *mocks.RedisStore, enc *mocks.Encoder) {
+		enc.On("Encode", value).
+			Return(t.GobBuf, nil)
+		m.On("EvalSha", mock.Anything, condSetScript.Hash(), []string{key}, t.GobBuf, "NX", tombstone, "KEEPTTL").
+			Return(redis.NewCmdResult(nil, errors.New("set error")))
+	})
+	ok, err := c.Add(ctx, key, value, options)
+	t.False(ok)
+	t.EqualError(err, "set error")
+}
+
+func TestOptions_Validate(t *testing.T) {
+	tt := map[string]struct {
+		input Options
+		want  any
+	}{
+		"Empty": {
+			Options{},
+			nil,
+		},
+		"Expiration": {
+			Options{Expiration: time.Minute},
+			nil,
+		},
+		"Expire At": {
+			Options{ExpireAt: time.Now().Add(time.Hour)},
+			nil,
+		},
+		"Keep TTL": {
+			Options{KeepTTL: true},
+			nil,
+		},
+		"Expiration & Expire At": {
+			Options{Expiration: time.Minute, ExpireAt: time.Now().Add(time.Hour)},
+			"only one of expiration, expire at and keep ttl",
+		},
+		"Expire At & Keep TTL": {
+			Options{ExpireAt: time.Now().Add(time.Hour), KeepTTL: true},
+			"only one of expiration, expire at and keep ttl",
+		},
+		"Expire At In Past": {
+			Options{ExpireAt: time.Now().Add(-time.Hour)},
+			"expire at must be in the future",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			err := test.input.validate()
+			if err != nil {
+				assert.Contains(t, err.Error(), test.want)
+				return
+			}
+			assert.Nil(t, test.want)
+		})
+	}
+}
+
 func (t *CacheTestSuite) TestCache_Delete() {
 	tt := map[string]struct {
 		value any
@@ -328,13 +534,13 @@ func (t *CacheTestSuite) TestCache_Invalidate() {
 			func(m *mocks.RedisStore, enc *mocks.Encoder) {
 				cmd := redis.NewStringSliceCmd(ctx)
 				cmd.SetVal([]string{key})
-				m.On("SMembers", ctx, "tag").
+				m.On("SMembers", mock.Anything, "tag").
 					Return(cmd)
 
-				m.On("Del", ctx, key).
+				m.On("Del", mock.Anything, key).
 					Return(redis.NewIntCmd(ctx, nil)).Once()
 
-				m.On("Del", ctx, "tag").
+				m.On("Del", mock.Anything, "tag").
 					Return(redis.NewIntCmd(ctx, nil)).Once()
 			},
 		},
@@ -348,7 +554,7 @@ func (t *CacheTestSuite) TestCache_Invalidate() {
 				cmd := redis.NewStringSliceCmd(ctx)
 				cmd.SetErr(errors.New("err"))
 
-				m.On("SMembers", ctx, "tag").
+				m.On("SMembers", mock.Anything, "tag").
 					Return(cmd)
 			},
 		},
@@ -364,8 +570,214 @@ func (t *CacheTestSuite) TestCache_Invalidate() {
 
 func (t *CacheTestSuite) TestCache_Flush() {
 	c := t.Setup(func(m *mocks.RedisStore, enc *mocks.Encoder) {
-		m.On("FlushAll", ctx).
+		m.On("FlushAll", mock.Anything).
 			Return(redis.NewStatusCmd(ctx, nil))
 	})
 	c.Flush(ctx)
 }
+
+func (t *CacheTestSuite) TestCache_Exists() {
+	tt := map[string]struct {
+		input []string
+		mock  func(m *mocks.RedisStore, enc *mocks.Encoder)
+		want  any
+	}{
+		"Success": {
+			[]string{key, "other"},
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("EvalSha", mock.Anything, existsScript.Hash(), []string{key, "other"}, tombstone).
+					Return(redis.NewCmdResult(int64(1), nil))
+			},
+			int64(1),
+		},
+		"No Keys": {
+			nil,
+			nil,
+			int64(0),
+		},
+		"Redis Error": {
+			[]string{key},
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("EvalSha", mock.Anything, existsScript.Hash(), []string{key}, tombstone).
+					Return(redis.NewCmdResult(nil, errors.New("exists error")))
+			},
+			"exists error",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func() {
+			c := t.Setup(test.mock)
+			got, err := c.Exists(ctx, test.input...)
+			if err != nil {
+				t.Contains(err.Error(), test.want)
+				return
+			}
+			t.Equal(test.want, got)
+		})
+	}
+}
+
+func (t *CacheTestSuite) TestCache_TTL() {
+	tt := map[string]struct {
+		mock func(m *mocks.RedisStore, enc *mocks.Encoder)
+		want any
+	}{
+		"Success": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("EvalSha", mock.Anything, ttlScript.Hash(), []string{key}, tombstone).
+					Return(redis.NewCmdResult(time.Minute.Milliseconds(), nil))
+			},
+			time.Minute,
+		},
+		"No Expiration": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("EvalSha", mock.Anything, ttlScript.Hash(), []string{key}, tombstone).
+					Return(redis.NewCmdResult(int64(-1), nil))
+			},
+			NoExpiration,
+		},
+		"Not Found": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("EvalSha", mock.Anything, ttlScript.Hash(), []string{key}, tombstone).
+					Return(redis.NewCmdResult(int64(-2), nil))
+			},
+			ErrNotFound.Error(),
+		},
+		"Redis Error": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("EvalSha", mock.Anything, ttlScript.Hash(), []string{key}, tombstone).
+					Return(redis.NewCmdResult(nil, errors.New("ttl error")))
+			},
+			"ttl error",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func() {
+			c := t.Setup(test.mock)
+			got, err := c.TTL(ctx, key)
+			if err != nil {
+				t.Contains(err.Error(), test.want)
+				return
+			}
+			t.Equal(test.want, got)
+		})
+	}
+}
+
+func (t *CacheTestSuite) TestCache_Touch() {
+	tt := map[string]struct {
+		exp  time.Duration
+		mock func(m *mocks.RedisStore, enc *mocks.Encoder)
+		want any
+	}{
+		"Success": {
+			time.Hour,
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Expire", mock.Anything, key, time.Hour).
+					Return(redis.NewBoolResult(true, nil))
+			},
+			nil,
+		},
+		"Not Found": {
+			time.Hour,
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Expire", mock.Anything, key, time.Hour).
+					Return(redis.NewBoolResult(false, nil))
+			},
+			ErrNotFound.Error(),
+		},
+		"Redis Error": {
+			time.Hour,
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Expire", mock.Anything, key, time.Hour).
+					Return(redis.NewBoolResult(false, errors.New("expire error")))
+			},
+			"expire error",
+		},
+		"Zero Expiration": {
+			0,
+			nil,
+			"expiration must be positive",
+		},
+		"Negative Expiration": {
+			-time.Second,
+			nil,
+			"expiration must be positive",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func() {
+			c := t.Setup(test.mock)
+			err := c.Touch(ctx, key, test.exp)
+			if err != nil {
+				t.Contains(err.Error(), test.want)
+				return
+			}
+			t.Equal(test.want, err)
+		})
+	}
+}
+
+func (t *CacheTestSuite) TestCache_Persist() {
+	tt := map[string]struct {
+		mock func(m *mocks.RedisStore, enc *mocks.Encoder)
+		want any
+	}{
+		"Success": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Persist", mock.Anything, key).
+					Return(redis.NewBoolResult(true, nil))
+			},
+			nil,
+		},
+		"No Expiration": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Persist", mock.Anything, key).
+					Return(redis.NewBoolResult(false, nil))
+				m.On("Exists", mock.Anything, key).
+					Return(redis.NewIntResult(1, nil))
+			},
+			nil,
+		},
+		"Not Found": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Persist", mock.Anything, key).
+					Return(redis.NewBoolResult(false, nil))
+				m.On("Exists", mock.Anything, key).
+					Return(redis.NewIntResult(0, nil))
+			},
+			ErrNotFound.Error(),
+		},
+		"Exists Error": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Persist", mock.Anything, key).
+					Return(redis.NewBoolResult(false, nil))
+				m.On("Exists", mock.Anything, key).
+					Return(redis.NewIntResult(0, errors.New("exists error")))
+			},
+			"exists error",
+		},
+		"Redis Error": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Persist", mock.Anything, key).
+					Return(redis.NewBoolResult(false, errors.New("persist error")))
+			},
+			"persist error",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func() {
+			c := t.Setup(test.mock)
+			err := c.Persist(ctx, key)
+			if err != nil {
+				t.Contains(err.Error(), test.want)
+				return
+			}
+			t.Equal(test.want, err)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+)
+
+type (
+	// RetryPolicy configures the retrying of transient Redis
+	// errors set via WithRetry. Only idempotent operations
+	// are retried: Get, Set, Delete and tag operations. Add,
+	// Replace and the counters are never retried.
+	RetryPolicy struct {
+		// MaxAttempts is the maximum number of times a
+		// command is attempted, including the first.
+		MaxAttempts int
+		// MinBackoff is the backoff before the first retry,
+		// which doubles for each subsequent retry.
+		MinBackoff time.Duration
+		// MaxBackoff is the upper limit of the backoff.
+		MaxBackoff time.Duration
+		// Retryable reports whether an error should be
+		// retried, defaults to IsRetryable.
+		Retryable func(err error) bool
+	}
+	// retrier retries functions in accordance with
+	// the RetryPolicy.
+	retrier struct {
+		policy RetryPolicy
+		sleep  func(ctx context.Context, d time.Duration) error
+	}
+)
+
+// retryablePrefixes are the prefixes of error replies from
+// Redis that indicate a transient condition, such as during
+// a failover.
+var retryablePrefixes = []string{
+	"LOADING ",
+	"READONLY ",
+	"CLUSTERDOWN ",
+	"TRYAGAIN ",
+	"MASTERDOWN ",
+}
+
+// WithRetry enables retrying of transient Redis errors using
+// exponential backoff with full jitter. Retries stop early if
+// the backoff would exceed the deadline of the context.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *config) {
+		if policy.MaxAttempts < 1 {
+			c.errs = append(c.errs, errors.New("redigo: retry max attempts must be at least one"))
+			return
+		}
+		if policy.MinBackoff <= 0 || policy.MaxBackoff < policy.MinBackoff {
+			c.errs = append(c.errs, errors.New("redigo: retry backoff must be positive and max backoff cannot be less than min backoff"))
+			return
+		}
+		if policy.Retryable == nil {
+			policy.Retryable = IsRetryable
+		}
+		c.retry = &retrier{policy: policy, sleep: sleep}
+	}
+}
+
+// IsRetryable reports whether the error is transient and the
+// command can be retried. Network errors, unexpected EOFs and
+// error replies during failovers are retryable, cancelled
+// contexts, misses and encoding errors are not.
+func IsRetryable(err error) bool {
+	if err == nil ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var rerr redis.Error
+	if errors.As(err, &rerr) {
+		for _, prefix := range retryablePrefixes {
+			if strings.HasPrefix(rerr.Error(), prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	var nerr net.Error
+	return errors.As(err, &nerr)
+}
+
+// do calls fn until it succeeds, the error is not retryable,
+// the max attempts have been made or the context is done.
+// The last error from fn is returned.
+func (r *retrier) do(ctx context.Context, fn func() error) error {
+	if r == nil {
+		return fn()
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= r.policy.MaxAttempts || !r.policy.Retryable(err) {
+			return err
+		}
+
+		wait := r.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return err
+		}
+
+		if r.sleep(ctx, wait) != nil {
+			return err
+		}
+	}
+}
+
+// backoff returns a random duration between zero and the
+// exponential backoff for the attempt.
+func (r *retrier) backoff(attempt int) time.Duration {
+	d := r.policy.MaxBackoff
+	if shift := attempt - 1; shift < 32 {
+		if exp := r.policy.MinBackoff << shift; exp > 0 && exp < d {
+			d = exp
+		}
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// sleep waits for the duration or until the context
+// is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"context"
+	"errors"
+	"github.com/ainsleyclark/redigo/mocks"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// timeoutError is a net.Error used for testing.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var _ net.Error = timeoutError{}
+
+// redisError is an error reply from Redis used for testing.
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+func (redisError) RedisError()     {}
+
+func (t *CacheTestSuite) TestRetry() {
+	c := t.Setup(func(m *mocks.RedisStore, enc *mocks.Encoder) {
+		m.On("Get", mock.Anything, key).
+			Return(redis.NewStringResult("", timeoutError{})).Once()
+		m.On("Get", mock.Anything, key).
+			Return(redis.NewStringResult("", redis.Nil)).Once()
+	})
+	c.cfg.retry = &retrier{
+		policy: RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Retryable: IsRetryable},
+		sleep:  func(context.Context, time.Duration) error { return nil },
+	}
+
+	err := c.Get(ctx, key, &testCacheStruct{})
+	t.ErrorIs(err, ErrNotFound)
+	c.client.(*mocks.RedisStore).AssertNumberOfCalls(t.T(), "Get", 2)
+}
+
+func (t *CacheTestSuite) TestRetry_Conditional() {
+	tt := map[string]struct {
+		mode string
+		fn   func(c *Cache) (bool, error)
+	}{
+		"Add": {
+			"NX",
+			func(c *Cache) (bool, error) {
+				return c.Add(ctx, key, value, options)
+			},
+		},
+		"Replace": {
+			"XX",
+			func(c *Cache) (bool, error) {
+				return c.Replace(ctx, key, value, options)
+			},
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func() {
+			c := t.Setup(func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				enc.On("Encode", value).
+					Return(t.GobBuf, nil)
+				m.On("EvalSha", mock.Anything, condSetScript.Hash(), []string{key}, t.GobBuf, test.mode, tombstone, "KEEPTTL").
+					Return(redis.NewCmdResult(nil, timeoutError{})).Once()
+				m.On("EvalSha", mock.Anything, condSetScript.Hash(), []string{key}, t.GobBuf, test.mode, tombstone, "KEEPTTL").
+					Return(redis.NewCmdResult(nil, redis.Nil)).Once()
+			})
+			c.cfg.retry = &retrier{
+				policy: RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Retryable: IsRetryable},
+				sleep:  func(context.Context, time.Duration) error { return nil },
+			}
+
+			ok, err := test.fn(c)
+			t.ErrorIs(err, timeoutError{})
+			t.False(ok)
+			c.client.(*mocks.RedisStore).AssertNumberOfCalls(t.T(), "EvalSha", 1)
+		})
+	}
+}
+
+func TestRetrier_Do(t *testing.T) {
+	tt := map[string]struct {
+		ctx   func() (context.Context, context.CancelFunc)
+		errs  []error
+		sleep error
+		want  error
+		calls int
+	}{
+		"Success": {
+			nil,
+			[]error{nil},
+			nil,
+			nil,
+			1,
+		},
+		"Retried": {
+			nil,
+			[]error{io.EOF, io.EOF, nil},
+			nil,
+			nil,
+			3,
+		},
+		"Max Attempts": {
+			nil,
+			[]error{io.EOF, io.EOF, io.EOF, nil},
+			nil,
+			io.EOF,
+			3,
+		},
+		"Not Retryable": {
+			nil,
+			[]error{redis.Nil},
+			nil,
+			redis.Nil,
+			1,
+		},
+		"Context Done": {
+			nil,
+			[]error{io.EOF, nil},
+			context.Canceled,
+			io.EOF,
+			1,
+		},
+		"Deadline Exceeded By Backoff": {
+			func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Nanosecond)
+			},
+			[]error{io.EOF, nil},
+			nil,
+			io.EOF,
+			1,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if test.ctx != nil {
+				ctx, cancel = test.ctx()
+			}
+			defer cancel()
+
+			r := &retrier{
+				policy: RetryPolicy{MaxAttempts: 3, MinBackoff: time.Second, MaxBackoff: time.Second, Retryable: IsRetryable},
+				sleep:  func(context.Context, time.Duration) error { return test.sleep },
+			}
+
+			calls := 0
+			err := r.do(ctx, func() error {
+				err := test.errs[calls]
+				calls++
+				return err
+			})
+			assert.Equal(t, test.want, err)
+			assert.Equal(t, test.calls, calls)
+		})
+	}
+}
+
+func TestRetrier_Nil(t *testing.T) {
+	var r *retrier
+	err := r.do(context.TODO(), func() error { return io.EOF })
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestRetrier_Backoff(t *testing.T) {
+	r := &retrier{policy: RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}}
+	for attempt := 1; attempt < 100; attempt++ {
+		got := r.backoff(attempt)
+		assert.GreaterOrEqual(t, got, time.Duration(0))
+		assert.LessOrEqual(t, got, 10*time.Millisecond)
+		if attempt == 1 {
+			assert.LessOrEqual(t, got, time.Millisecond)
+		}
+	}
+}
+
+func TestSleep(t *testing.T) {
+	assert.NoError(t, sleep(context.Background(), time.Nanosecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, sleep(ctx, time.Hour), context.Canceled)
+}
+
+func TestIsRetryable(t *testing.T) {
+	tt := map[string]struct {
+		input error
+		want  bool
+	}{
+		"Nil":         {nil, false},
+		"Canceled":    {context.Canceled, false},
+		"Deadline":    {context.DeadlineExceeded, false},
+		"Redis Nil":   {redis.Nil, false},
+		"Not Found":   {ErrNotFound, false},
+		"Encode":      {&EncodeError{Err: errors.New("err")}, false},
+		"EOF":         {io.EOF, true},
+		"Timeout":     {timeoutError{}, true},
+		"Loading":     {redisError("LOADING Redis is loading the dataset in memory"), true},
+		"Read Only":   {redisError("READONLY You can't write against a read only replica."), true},
+		"Wrong Type":  {redisError("WRONGTYPE Operation against a key holding the wrong kind of value"), false},
+		"Other Error": {errors.New("error"), false},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.want, IsRetryable(test.input))
+		})
+	}
+}
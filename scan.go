@@ -0,0 +1,192 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"strings"
+)
+
+// scanCount is the number of keys requested from SCAN on
+// each call, which is also the size of the batches removed
+// by DeletePattern.
+const scanCount = 1000
+
+// KeyIterator iterates over the keys of the cache matching
+// a pattern, retrieving them a page at a time with SCAN. It
+// is obtained via Keys.
+//
+//	it := c.Keys(ctx, "user:*")
+//	for it.Next() {
+//		fmt.Println(it.Key())
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type KeyIterator struct {
+	ctx    context.Context
+	c      *Cache
+	match  string
+	cursor uint64
+	done   bool
+	page   []string
+	key    string
+	err    error
+}
+
+// Keys returns an iterator over the keys of the cache that
+// match the glob style pattern, such as "user:*", without
+// blocking Redis as KEYS does. The pattern and the keys
+// returned exclude the prefix set via WithPrefix, and only
+// keys with the prefix are matched. Tag sets and metadata
+// stored by RediGo are excluded.
+//
+// As with SCAN, keys may be returned more than once, and
+// keys added or removed during iteration may be missed.
+func (c *Cache) Keys(ctx context.Context, pattern string) *KeyIterator {
+	return &KeyIterator{
+		ctx:   ctx,
+		c:     c,
+		match: escapeGlob(c.cfg.prefix) + pattern,
+	}
+}
+
+// Next advances the iterator to the next key, retrieving the
+// next page of keys if needed. It returns false once there
+// are no more keys or an error occurred.
+func (it *KeyIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.err = it.scan()
+	}
+	it.key, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Key returns the current key, without the prefix.
+func (it *KeyIterator) Key() string {
+	return it.key
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *KeyIterator) Err() error {
+	return it.err
+}
+
+// scan retrieves the next page of keys.
+func (it *KeyIterator) scan() error {
+	c := it.c
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.do(it.ctx, &Event{Operation: OpScan}, func(ctx context.Context, e *Event) error {
+		var (
+			keys   []string
+			cursor uint64
+		)
+		err := c.retry(ctx, func() (err error) {
+			// Values are strings, which excludes tag sets.
+			keys, cursor, err = c.client.ScanType(ctx, it.cursor, it.match, scanCount, "string").Result()
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range keys {
+			if isInternalKey(k) {
+				continue
+			}
+			it.page = append(it.page, strings.TrimPrefix(k, c.cfg.prefix))
+		}
+		it.cursor = cursor
+		it.done = cursor == 0
+
+		return nil
+	})
+}
+
+// DeletePattern removes the keys of the cache that match the
+// glob style pattern, as matched by Keys, along with their
+// metadata and chunks. Keys are removed in batches using
+// UNLINK, which frees memory in the background.
+func (c *Cache) DeletePattern(ctx context.Context, pattern string) error {
+	it := c.Keys(ctx, pattern)
+	batch := make([]string, 0, scanCount)
+	for it.Next() {
+		batch = append(batch, it.Key())
+		if len(batch) < scanCount {
+			continue
+		}
+		err := c.unlink(ctx, batch)
+		if err != nil {
+			return err
+		}
+		batch = batch[:0]
+	}
+	if it.Err() != nil {
+		return it.Err()
+	}
+	return c.unlink(ctx, batch)
+}
+
+// unlink removes the batch of keys along with their sliding
+// expiration metadata and chunks.
+func (c *Cache) unlink(ctx context.Context, batch []string) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.do(ctx, &Event{Operation: OpDeletePattern}, func(ctx context.Context, e *Event) error {
+		keys := make([]string, len(batch))
+		for i, key := range batch {
+			keys[i] = c.cfg.prefix + key
+		}
+
+		heads := make([]*redis.StringCmd, len(keys))
+		err := c.pipeChunks(ctx, keys, func(pipe redis.Pipeliner, i int, key string) {
+			heads[i] = pipe.GetRange(ctx, key, 0, manifestMaxLen-1)
+		})
+		if err != nil {
+			return err
+		}
+
+		all := c.slidingKeys(keys...)
+		for i, head := range heads {
+			m, _ := parseManifest([]byte(head.Val()))
+			all = append(all, m.keys(keys[i])...)
+		}
+
+		return c.retry(ctx, func() error {
+			return c.client.Unlink(ctx, all...).Err()
+		})
+	})
+}
+
+// isInternalKey reports whether the key holds metadata or
+// chunks stored by RediGo, rather than a value.
+func isInternalKey(key string) bool {
+	return strings.HasSuffix(key, slidingSuffix) || strings.Contains(key, chunkSuffix)
+}
+
+// escapeGlob escapes the special characters of glob style
+// patterns in s, so it is matched literally.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ainsleyclark/redigo/mocks"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/mock"
+	"sort"
+	"time"
+)
+
+func (t *CacheTestSuite) TestCache_Keys() {
+	t.Run("Prefixed", func() {
+		c, mr := t.SetupRedis(WithPrefix("prefix"), WithSlidingExpiration(), WithChunkSize(100))
+		t.NoError(c.Set(ctx, "user:1", value, Options{Expiration: time.Minute, Tags: []string{"user:tag"}}))
+		t.NoError(c.Set(ctx, "user:2", largeValue, Options{}))
+		t.NoError(c.Set(ctx, "post:1", value, Options{}))
+		t.NoError(mr.Set("user:3", "unprefixed"))
+
+		t.Equal([]string{"user:1", "user:2"}, t.keys(c, "user:*"))
+		t.Equal([]string{"post:1", "user:1", "user:2"}, t.keys(c, "*"))
+	})
+
+	t.Run("Escaped Prefix", func() {
+		c, mr := t.SetupRedis(WithPrefix("app*"))
+		t.NoError(c.Set(ctx, key, value, Options{}))
+		t.NoError(mr.Set("application:key", "value"))
+		t.Equal([]string{key}, t.keys(c, "*"))
+	})
+
+	t.Run("Pages", func() {
+		c, mr := t.SetupRedis()
+		for i := 0; i < scanCount*2+1; i++ {
+			t.NoError(mr.Set(fmt.Sprintf("key:%d", i), "value"))
+		}
+		t.Len(t.keys(c, "key:*"), scanCount*2+1)
+	})
+
+	t.Run("Error", func() {
+		c := t.Setup(func(m *mocks.RedisStore, enc *mocks.Encoder) {
+			m.On("ScanType", mock.Anything, uint64(0), "*", int64(scanCount), "string").
+				Return(redis.NewScanCmdResult(nil, 0, errors.New("scan error")))
+		})
+		it := c.Keys(ctx, "*")
+		t.False(it.Next())
+		t.EqualError(it.Err(), "scan error")
+	})
+}
+
+func (t *CacheTestSuite) TestCache_DeletePattern() {
+	t.Run("Deleted", func() {
+		c, mr := t.SetupRedis(WithSlidingExpiration(), WithChunkSize(100))
+		t.NoError(c.Set(ctx, "user:1", value, Options{Expiration: time.Minute, Tags: []string{tag}}))
+		t.NoError(c.Set(ctx, "user:2", largeValue, Options{}))
+		t.NoError(c.Set(ctx, "post:1", value, Options{}))
+
+		t.NoError(c.DeletePattern(ctx, "user:*"))
+		t.Equal([]string{"post:1", tag}, mr.Keys())
+	})
+
+	t.Run("Batches", func() {
+		c, mr := t.SetupRedis()
+		for i := 0; i < scanCount+1; i++ {
+			t.NoError(mr.Set(fmt.Sprintf("key:%d", i), "value"))
+		}
+		t.NoError(c.DeletePattern(ctx, "key:*"))
+		t.Empty(mr.Keys())
+	})
+
+	t.Run("No Match", func() {
+		c, _ := t.SetupRedis()
+		t.NoError(c.DeletePattern(ctx, "*"))
+	})
+
+	t.Run("Error", func() {
+		c := t.Setup(func(m *mocks.RedisStore, enc *mocks.Encoder) {
+			m.On("ScanType", mock.Anything, uint64(0), "*", int64(scanCount), "string").
+				Return(redis.NewScanCmdResult(nil, 0, errors.New("scan error")))
+		})
+		t.EqualError(c.DeletePattern(ctx, "*"), "scan error")
+	})
+}
+
+// keys returns the sorted keys matching the pattern.
+func (t *CacheTestSuite) keys(c *Cache, pattern string) []string {
+	var keys []string
+	it := c.Keys(ctx, pattern)
+	for it.Next() {
+		keys = append(keys, it.Key())
+	}
+	t.NoError(it.Err())
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,201 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// slidingSuffix is appended to a key to obtain the key
+// holding its sliding expiration metadata.
+const slidingSuffix = ":redigo:sliding"
+
+// slidingScript retrieves the value of KEYS[1] and, if its
+// metadata exists at KEYS[2], resets the expiration of the
+// value and metadata to the original expiration with GETEX.
+// The value is returned followed by the tags listed in the
+// metadata, which are refreshed by the caller so the script
+// only accesses the keys it declares. An empty reply is
+// returned if the value does not exist.
+//
+// The metadata is the expiration in milliseconds followed by
+// the tags of the value, separated by new lines.
+var slidingScript = redis.NewScript(`
+local meta = redis.call('GET', KEYS[2])
+if not meta then
+	local value = redis.call('GET', KEYS[1])
+	if not value then
+		return {}
+	end
+	return {value}
+end
+local reply = {}
+for part in string.gmatch(meta, '[^\n]+') do
+	table.insert(reply, part)
+end
+local ttl = reply[1]
+local value = redis.call('GETEX', KEYS[1], 'PX', ttl)
+if not value then
+	return {}
+end
+redis.call('PEXPIRE', KEYS[2], ttl)
+reply[1] = value
+return reply
+`)
+
+// WithSlidingExpiration enables sliding expiration. Every
+// successful Get resets the expiration of the key to the
+// expiration it was set with, along with the expiration of
+// its tag sets, so values live as long as they are used.
+//
+// Values without an expiration are not affected. Sliding
+// expiration requires Redis 6.2 or later.
+func WithSlidingExpiration() Option {
+	return func(c *config) {
+		c.sliding = true
+	}
+}
+
+// fetch retrieves the raw value of the prefixed key, resetting
+// its expiration if sliding expiration is enabled. Without
+// sliding expiration, the value is not copied from the reply.
+func (c *Cache) fetch(ctx context.Context, key string) ([]byte, error) {
+	if !c.cfg.sliding {
+		return c.client.Get(ctx, key).Bytes()
+	}
+	keys := []string{key, key + slidingSuffix}
+	reply, err := slidingScript.Run(ctx, c.client, keys).Slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(reply) == 0 {
+		return nil, redis.Nil
+	}
+	c.expireTags(ctx, key, reply[1:])
+	s, _ := reply[0].(string)
+	return []byte(s), nil
+}
+
+// expireTags resets the expiration of the prefixed tags of
+// the prefixed key to the tag expiration, in a single
+// pipeline. Errors are logged.
+func (c *Cache) expireTags(ctx context.Context, key string, tags []any) {
+	if len(tags) == 0 {
+		return
+	}
+	err := c.retry(ctx, func() error {
+		_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, tag := range tags {
+				s, _ := tag.(string)
+				pipe.PExpire(ctx, s, c.cfg.tagExpiration)
+			}
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		c.cfg.logger.Printf("redigo: error refreshing tags of key %s: %s", key, err.Error())
+	}
+}
+
+// writeSliding stores the sliding expiration metadata of the
+// prefixed key if sliding expiration is enabled. Values set
+// without an expiration or with ExpireAt have their metadata
+// removed, and values set with KeepTTL are left untouched.
+func (c *Cache) writeSliding(ctx context.Context, key string, exp time.Duration, tags []string) error {
+	if !c.cfg.sliding || exp < 0 {
+		return nil
+	}
+
+	meta := key + slidingSuffix
+	if exp == 0 {
+		return c.retry(ctx, func() error {
+			return c.client.Del(ctx, meta).Err()
+		})
+	}
+
+	lines := make([]string, 0, len(tags)+1)
+	lines = append(lines, strconv.FormatInt(exp.Milliseconds(), 10))
+	for _, tag := range tags {
+		lines = append(lines, c.cfg.prefix+tag)
+	}
+
+	return c.retry(ctx, func() error {
+		return c.client.Set(ctx, meta, strings.Join(lines, "\n"), exp).Err()
+	})
+}
+
+// touchSliding sets the expiration that the prefixed key and
+// its chunks, described by the manifest, slide by to exp,
+// as for Touch. If exp is not positive, as for Persist, the
+// sliding expiration is removed. Keys without sliding
+// expiration metadata are left untouched.
+func (c *Cache) touchSliding(ctx context.Context, key string, m manifest, exp time.Duration) error {
+	if !c.cfg.sliding {
+		return nil
+	}
+
+	meta := key + slidingSuffix
+	if exp <= 0 {
+		err := c.retry(ctx, func() error {
+			return c.client.Del(ctx, meta).Err()
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		var data string
+		err := c.retry(ctx, func() (err error) {
+			data, err = c.client.Get(ctx, meta).Result()
+			return err
+		})
+		if errors.Is(err, redis.Nil) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		lines := strings.Split(data, "\n")
+		lines[0] = strconv.FormatInt(exp.Milliseconds(), 10)
+		err = c.retry(ctx, func() error {
+			return c.client.Set(ctx, meta, strings.Join(lines, "\n"), exp).Err()
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.sliding == 0 {
+		return nil
+	}
+	if exp < 0 {
+		exp = 0
+	}
+	m.sliding = exp
+	err := c.retry(ctx, func() error {
+		return c.client.SetArgs(ctx, key, m.encode(), redis.SetArgs{Mode: "XX", KeepTTL: true}).Err()
+	})
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
+
+// slidingKeys returns the prefixed keys passed along with
+// their sliding expiration metadata keys, if enabled.
+func (c *Cache) slidingKeys(keys ...string) []string {
+	if !c.cfg.sliding {
+		return keys
+	}
+	all := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		all = append(all, key, key+slidingSuffix)
+	}
+	return all
+}
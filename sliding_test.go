@@ -0,0 +1,126 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"github.com/ainsleyclark/redigo/mocks"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+func (t *CacheTestSuite) TestSlidingExpiration() {
+	c, mr := t.SetupRedis(WithSlidingExpiration(), WithPrefix("prefix"), WithTagExpiration(time.Hour))
+
+	err := c.Set(ctx, key, value, Options{Expiration: time.Minute, Tags: []string{tag}})
+	t.NoError(err)
+	t.Equal("60000\nprefix:tag", t.mustGet(mr, "prefix:key"+slidingSuffix))
+
+	mr.FastForward(50 * time.Second)
+	mr.SetTTL("prefix:tag", time.Second)
+
+	var got testCacheStruct
+	t.NoError(c.Get(ctx, key, &got))
+	t.Equal(value, got)
+	t.Equal(time.Minute, mr.TTL("prefix:key"))
+	t.Equal(time.Minute, mr.TTL("prefix:key"+slidingSuffix))
+	t.Equal(time.Hour, mr.TTL("prefix:tag"))
+
+	mr.FastForward(50 * time.Second)
+	t.NoError(c.Get(ctx, key, &got))
+
+	t.Run("Not Found", func() {
+		t.ErrorIs(c.Get(ctx, "missing", &got), ErrNotFound)
+	})
+
+	t.Run("Without Expiration", func() {
+		t.NoError(c.Set(ctx, key, value, Options{}))
+		t.False(mr.Exists("prefix:key" + slidingSuffix))
+		t.NoError(c.Get(ctx, key, &got))
+		t.Equal(time.Duration(0), mr.TTL("prefix:key"))
+	})
+
+	t.Run("Delete", func() {
+		t.NoError(c.Set(ctx, key, value, Options{Expiration: time.Minute}))
+		t.NoError(c.Delete(ctx, key))
+		t.False(mr.Exists("prefix:key"))
+		t.False(mr.Exists("prefix:key" + slidingSuffix))
+	})
+}
+
+func (t *CacheTestSuite) TestSlidingExpiration_Touch() {
+	t.Run("Value", func() {
+		c, mr := t.SetupRedis(WithSlidingExpiration())
+		t.NoError(c.Set(ctx, key, value, Options{Expiration: time.Minute, Tags: []string{tag}}))
+		t.NoError(c.Touch(ctx, key, time.Hour))
+		t.Equal("3600000\ntag", t.mustGet(mr, key+slidingSuffix))
+
+		var got testCacheStruct
+		t.NoError(c.Get(ctx, key, &got))
+		t.Equal(time.Hour, mr.TTL(key))
+	})
+
+	t.Run("Chunked", func() {
+		c, mr := t.SetupRedis(WithSlidingExpiration(), WithChunkSize(100))
+		t.NoError(c.Set(ctx, key, largeValue, Options{Expiration: time.Minute}))
+		t.NoError(c.Touch(ctx, key, time.Hour))
+
+		var got string
+		t.NoError(c.Get(ctx, key, &got))
+		t.Equal(largeValue, got)
+		t.Equal(time.Hour, mr.TTL(key))
+		for _, k := range t.chunkKeys(mr) {
+			t.Equal(time.Hour, mr.TTL(k))
+		}
+	})
+}
+
+func (t *CacheTestSuite) TestSlidingExpiration_Persist() {
+	t.Run("Value", func() {
+		c, mr := t.SetupRedis(WithSlidingExpiration())
+		t.NoError(c.Set(ctx, key, value, Options{Expiration: time.Minute}))
+		t.NoError(c.Persist(ctx, key))
+		t.False(mr.Exists(key + slidingSuffix))
+
+		var got testCacheStruct
+		t.NoError(c.Get(ctx, key, &got))
+		t.Equal(time.Duration(0), mr.TTL(key))
+	})
+
+	t.Run("Chunked", func() {
+		c, mr := t.SetupRedis(WithSlidingExpiration(), WithChunkSize(100))
+		t.NoError(c.Set(ctx, key, largeValue, Options{Expiration: time.Minute}))
+		t.NoError(c.Persist(ctx, key))
+
+		var got string
+		t.NoError(c.Get(ctx, key, &got))
+		t.Equal(largeValue, got)
+		t.Equal(time.Duration(0), mr.TTL(key))
+		for _, k := range t.chunkKeys(mr) {
+			t.Equal(time.Duration(0), mr.TTL(k))
+		}
+	})
+}
+
+func (t *CacheTestSuite) TestSlidingExpiration_Mock() {
+	c := t.Setup(func(m *mocks.RedisStore, enc *mocks.Encoder) {
+		m.On("EvalSha", mock.Anything, slidingScript.Hash(), []string{key, key + slidingSuffix}).
+			Return(redis.NewCmdResult([]any{string(t.GobBuf)}, nil))
+		enc.On("Decode", t.GobBuf, mock.Anything).
+			Return(nil)
+	})
+	c.cfg.sliding = true
+
+	t.NoError(c.Get(ctx, key, &testCacheStruct{}))
+}
+
+// mustGet returns the string value of the key from the
+// in-memory Redis server.
+func (t *CacheTestSuite) mustGet(mr *miniredis.Miniredis, key string) string {
+	val, err := mr.Get(key)
+	t.NoError(err)
+	return val
+}
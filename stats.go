@@ -0,0 +1,161 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Stats is a snapshot of the activity of a Cache, or
+	// of a single key group within it.
+	Stats struct {
+		// Hits is the number of Get calls that found
+		// the key.
+		Hits uint64
+		// Misses is the number of Get calls for keys that
+		// did not exist.
+		Misses uint64
+		// Errors is the number of failed operations,
+		// excluding misses.
+		Errors uint64
+		// Encodes is the number of values encoded.
+		Encodes uint64
+		// EncodeDuration is the total time spent encoding.
+		EncodeDuration time.Duration
+		// Decodes is the number of values decoded.
+		Decodes uint64
+		// DecodeDuration is the total time spent decoding.
+		DecodeDuration time.Duration
+		// BytesRead is the total size of encoded values
+		// retrieved by Get.
+		BytesRead uint64
+		// BytesWritten is the total size of encoded values
+		// stored by Set, Add, Replace and Update.
+		BytesWritten uint64
+		// InvalidatedKeys is the number of keys removed
+		// by Invalidate.
+		InvalidatedKeys uint64
+	}
+	// stats records the Stats of a Cache per key group.
+	stats struct {
+		mtx    sync.Mutex
+		group  func(key string) string
+		groups map[string]*Stats
+	}
+)
+
+// WithKeyGroup sets the function used to assign keys to a
+// group, allowing stats to be broken down by the type of
+// value stored. For example, returning "user" for the
+// key "user:1". By default, all keys share the same
+// unnamed group.
+func WithKeyGroup(fn func(key string) string) Option {
+	return func(c *config) {
+		if fn == nil {
+			c.errs = append(c.errs, errors.New("redigo: key group function cannot be nil"))
+			return
+		}
+		c.stats.group = fn
+	}
+}
+
+// Namespace returns the prefix set via WithPrefix without
+// the separator, or an empty string if none was set.
+func (c *Cache) Namespace() string {
+	return strings.TrimSuffix(c.cfg.prefix, prefixSeparator)
+}
+
+// Stats returns a snapshot of the activity of the cache
+// across all key groups.
+func (c *Cache) Stats() Stats {
+	var total Stats
+	for _, s := range c.GroupStats() {
+		total.add(s)
+	}
+	return total
+}
+
+// GroupStats returns a snapshot of the activity of the
+// cache by key group, as set via WithKeyGroup.
+func (c *Cache) GroupStats() map[string]Stats {
+	c.cfg.stats.mtx.Lock()
+	defer c.cfg.stats.mtx.Unlock()
+	groups := make(map[string]Stats, len(c.cfg.stats.groups))
+	for name, s := range c.cfg.stats.groups {
+		groups[name] = *s
+	}
+	return groups
+}
+
+// HitRatio returns the ratio of hits to the total number
+// of lookups, or zero if there have been none.
+func (s Stats) HitRatio() float64 {
+	lookups := s.Hits + s.Misses
+	if lookups == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(lookups)
+}
+
+// add adds the counters of o to s.
+func (s *Stats) add(o Stats) {
+	s.Hits += o.Hits
+	s.Misses += o.Misses
+	s.Errors += o.Errors
+	s.Encodes += o.Encodes
+	s.EncodeDuration += o.EncodeDuration
+	s.Decodes += o.Decodes
+	s.DecodeDuration += o.DecodeDuration
+	s.BytesRead += o.BytesRead
+	s.BytesWritten += o.BytesWritten
+	s.InvalidatedKeys += o.InvalidatedKeys
+}
+
+// newStats returns stats with all keys in a single group.
+func newStats() *stats {
+	return &stats{
+		group:  func(string) string { return "" },
+		groups: make(map[string]*Stats),
+	}
+}
+
+// record applies fn to the stats of the group the key
+// belongs to.
+func (s *stats) record(key string, fn func(s *Stats)) {
+	group := s.group(key)
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	g, ok := s.groups[group]
+	if !ok {
+		g = &Stats{}
+		s.groups[group] = g
+	}
+	fn(g)
+}
+
+// event records the outcome of the event.
+func (s *stats) event(e *Event) {
+	s.record(e.Key, func(s *Stats) {
+		switch {
+		case errors.Is(e.Err, ErrNotFound):
+			if e.Operation == OpGet {
+				s.Misses++
+			}
+		case e.Err != nil:
+			s.Errors++
+		case e.Operation == OpGet:
+			s.Hits++
+			s.BytesRead += uint64(e.Size)
+		case e.Operation == OpSet, e.Operation == OpUpdate,
+			e.Operation == OpAdd && !e.Hit,
+			e.Operation == OpReplace && e.Hit:
+			s.BytesWritten += uint64(e.Size)
+		}
+	})
+}
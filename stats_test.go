@@ -0,0 +1,88 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"errors"
+	"github.com/ainsleyclark/redigo/mocks"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+)
+
+func (t *CacheTestSuite) TestStats() {
+	c := t.Setup(func(m *mocks.RedisStore, enc *mocks.Encoder) {
+		m.On("Get", mock.Anything, "user:1").
+			Return(redis.NewStringResult(string(t.GobBuf), nil))
+		m.On("Get", mock.Anything, "user:2").
+			Return(redis.NewStringResult("", redis.Nil))
+		m.On("Get", mock.Anything, "post:1").
+			Return(redis.NewStringResult("", errors.New("error")))
+		enc.On("Decode", t.GobBuf, mock.Anything).
+			Return(nil)
+
+		enc.On("Encode", value).
+			Return(t.GobBuf, nil)
+		m.On("Set", mock.Anything, "post:1", t.GobBuf, options.Expiration).
+			Return(redis.NewStatusCmd(ctx, nil))
+		m.On("SAdd", mock.Anything, tag, "post:1").
+			Return(redis.NewIntCmd(ctx, nil))
+		m.On("Expire", mock.Anything, tag, DefaultTagExpiration).
+			Return(redis.NewBoolCmd(ctx, true))
+
+		members := redis.NewStringSliceCmd(ctx)
+		members.SetVal([]string{"post:1", "post:2"})
+		m.On("SMembers", mock.Anything, tag).
+			Return(members)
+		m.On("Del", mock.Anything, mock.Anything).
+			Return(redis.NewIntCmd(ctx, nil))
+	})
+	c.cfg.stats.group = func(key string) string {
+		return strings.Split(key, ":")[0]
+	}
+
+	t.NoError(c.Get(ctx, "user:1", &testCacheStruct{}))
+	t.ErrorIs(c.Get(ctx, "user:2", &testCacheStruct{}), ErrNotFound)
+	t.Error(c.Get(ctx, "post:1", &testCacheStruct{}))
+	t.NoError(c.Set(ctx, "post:1", value, options))
+	c.Invalidate(ctx, []string{tag})
+
+	groups := c.GroupStats()
+	t.Len(groups, 3)
+
+	user := groups["user"]
+	t.Equal(uint64(1), user.Hits)
+	t.Equal(uint64(1), user.Misses)
+	t.Equal(uint64(0), user.Errors)
+	t.Equal(uint64(1), user.Decodes)
+	t.Equal(uint64(len(t.GobBuf)), user.BytesRead)
+
+	post := groups["post"]
+	t.Equal(uint64(1), post.Errors)
+	t.Equal(uint64(1), post.Encodes)
+	t.Equal(uint64(len(t.GobBuf)), post.BytesWritten)
+	t.Equal(uint64(2), post.InvalidatedKeys)
+
+	total := c.Stats()
+	t.Equal(uint64(1), total.Hits)
+	t.Equal(uint64(1), total.Misses)
+	t.Equal(uint64(1), total.Errors)
+	t.Equal(uint64(2), total.InvalidatedKeys)
+	t.Equal(0.5, total.HitRatio())
+}
+
+func TestStats_HitRatio(t *testing.T) {
+	assert.Equal(t, float64(0), Stats{}.HitRatio())
+	assert.Equal(t, 0.75, Stats{Hits: 3, Misses: 1}.HitRatio())
+}
+
+func TestCache_Namespace(t *testing.T) {
+	cfg, err := newConfig(NewGobEncoder(), WithPrefix("app"))
+	assert.NoError(t, err)
+	assert.Equal(t, "app", (&Cache{cfg: cfg}).Namespace())
+	assert.Equal(t, "", (&Cache{}).Namespace())
+}
@@ -0,0 +1,220 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"io"
+	"time"
+)
+
+// DefaultStreamChunkSize is the size of the chunks written
+// by SetStream if no chunk size is set via WithChunkSize.
+const DefaultStreamChunkSize = 512 << 10
+
+// ioError wraps errors from the io.Reader or io.Writer
+// passed to SetStream and GetStream, which do not indicate
+// a failure of Redis.
+type ioError struct {
+	err error
+}
+
+func (e ioError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error from the io.Reader or io.Writer.
+func (e ioError) Unwrap() error {
+	return e.err
+}
+
+// SetStream stores the contents of the reader under the key
+// as is, bypassing the Encoder, for large blobs such as
+// files. The reader is consumed in chunks of the size set
+// via WithChunkSize, or DefaultStreamChunkSize, each being
+// written before the next is read so memory use is bounded
+// by the chunk size. Contents larger than a single chunk
+// are stored as a chunked value, which Get reads with an
+// Encoder such as NewRawEncoder.
+//
+// A ValueTooLargeError is returned once the contents exceed
+// the size set via WithMaxValueSize, leaving the key
+// untouched. Errors from the reader are returned as is.
+func (c *Cache) SetStream(ctx context.Context, key string, r io.Reader, options Options) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	err := options.validate()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := timeoutOverride(ctx, options.Timeout)
+	defer cancel()
+
+	e := &Event{Operation: OpSet, Key: key, Tags: c.tags(options.Tags)}
+
+	return c.do(ctx, e, func(ctx context.Context, e *Event) error {
+		k, err := c.key(key)
+		if err != nil {
+			return err
+		}
+
+		exp := c.expiration(options)
+		old, ttl, err := c.readManifest(ctx, k)
+		if err != nil {
+			return err
+		}
+
+		buf, keys, size, err := c.streamChunks(ctx, key, k, r, exp, ttl, options)
+		if err != nil {
+			return err
+		}
+		e.Size = size
+
+		_, err = c.store(ctx, k, buf, chunkWrite{keys: keys, stale: old.keys(k)}, "", exp, options, e.Tags)
+		return err
+	})
+}
+
+// GetStream writes the value of the key to the writer as is,
+// bypassing the Encoder. Chunked values are retrieved one
+// chunk at a time, so memory use is bounded by the chunk
+// size. ErrNotFound is returned if the key or any of its
+// chunks does not exist, in which case part of the value
+// may have been written. Errors from the writer are returned
+// as is.
+func (c *Cache) GetStream(ctx context.Context, key string, w io.Writer) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.do(ctx, &Event{Operation: OpGet, Key: key}, func(ctx context.Context, e *Event) error {
+		k, err := c.key(key)
+		if err != nil {
+			return err
+		}
+
+		var buf []byte
+		err = c.retry(ctx, func() error {
+			buf, err = c.fetch(ctx, k)
+			return err
+		})
+		if errors.Is(err, redis.Nil) {
+			return ErrNotFound
+		} else if err != nil {
+			return err
+		}
+
+		if isTombstone(buf) {
+			return errTombstone
+		}
+
+		m, ok := parseManifest(buf)
+		if !ok {
+			e.Hit = true
+			e.Size = len(buf)
+			return writeStream(w, buf)
+		}
+
+		for i := 0; i < m.chunks; i++ {
+			var cmd *redis.StringCmd
+			err := c.pipeChunks(ctx, []string{m.key(k, i)}, func(pipe redis.Pipeliner, _ int, key string) {
+				cmd = pipe.Get(ctx, key)
+				if m.sliding > 0 {
+					pipe.PExpire(ctx, key, m.sliding)
+				}
+			})
+			if errors.Is(err, redis.Nil) {
+				return ErrNotFound
+			} else if err != nil {
+				return err
+			}
+			b, _ := cmd.Bytes()
+			err = writeStream(w, b)
+			if err != nil {
+				return err
+			}
+		}
+
+		e.Hit = true
+		e.Size = m.size
+
+		return nil
+	})
+}
+
+// streamChunks reads the reader in chunks, storing each under
+// a chunk key of the prefixed key k. Contents that fit in a
+// single chunk are returned without being stored. Otherwise,
+// the manifest is returned along with the chunk keys. The
+// size of the contents is returned in both cases.
+func (c *Cache) streamChunks(ctx context.Context, key, k string, r io.Reader, exp, ttl time.Duration, options Options) ([]byte, []string, int, error) {
+	m, err := c.newManifest(exp)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	var (
+		buf  = make([]byte, c.streamChunkSize())
+		args = chunkArgs(exp, ttl, options)
+		keys []string
+	)
+	for {
+		n, err := io.ReadFull(r, buf)
+		eof := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !eof {
+			c.deleteChunks(ctx, keys)
+			return nil, nil, 0, ioError{err}
+		}
+
+		if max := c.cfg.maxValueSize; max > 0 && m.size+n > max {
+			c.deleteChunks(ctx, keys)
+			return nil, nil, 0, &ValueTooLargeError{Key: key, Size: m.size + n, Max: max}
+		}
+
+		if eof && m.chunks == 0 {
+			return buf[:n], nil, n, nil
+		}
+
+		if n > 0 {
+			chunk := m.key(k, m.chunks)
+			err = c.retry(ctx, func() error {
+				return c.client.SetArgs(ctx, chunk, buf[:n], args).Err()
+			})
+			if err != nil {
+				c.deleteChunks(ctx, keys)
+				return nil, nil, 0, err
+			}
+			keys = append(keys, chunk)
+			m.chunks++
+			m.size += n
+		}
+
+		if eof {
+			return m.encode(), keys, m.size, nil
+		}
+	}
+}
+
+// streamChunkSize returns the size of the chunks written by
+// SetStream.
+func (c *Cache) streamChunkSize() int {
+	if c.cfg.chunkSize > 0 {
+		return c.cfg.chunkSize
+	}
+	return DefaultStreamChunkSize
+}
+
+// writeStream writes the buffer to the writer, wrapping any
+// error in an ioError.
+func writeStream(w io.Writer, buf []byte) error {
+	_, err := w.Write(buf)
+	if err != nil {
+		return ioError{err}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"bytes"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"io"
+	"strings"
+	"testing/iotest"
+	"time"
+)
+
+func (t *CacheTestSuite) TestCache_SetStream() {
+	t.Run("Single Chunk", func() {
+		c, mr := t.SetupRedis(WithChunkSize(100))
+		t.NoError(c.SetStream(ctx, key, strings.NewReader("blob"), Options{}))
+		t.Equal("blob", t.mustGet(mr, key))
+	})
+
+	t.Run("Chunked", func() {
+		c, mr := t.SetupRedis(WithChunkSize(100))
+		err := c.SetStream(ctx, key, iotest.OneByteReader(strings.NewReader(largeValue)), Options{Expiration: time.Minute})
+		t.NoError(err)
+
+		m, ok := parseManifest([]byte(t.mustGet(mr, key)))
+		t.True(ok)
+		t.Equal(6, m.chunks)
+		t.Equal(len(largeValue), m.size)
+		for _, k := range m.keys(key) {
+			t.Equal(time.Minute, mr.TTL(k))
+		}
+
+		buf := bytes.Buffer{}
+		t.NoError(c.GetStream(ctx, key, &buf))
+		t.Equal(largeValue, buf.String())
+	})
+
+	t.Run("Get", func() {
+		mr := miniredis.RunT(t.T())
+		c, err := New(&redis.Options{Addr: mr.Addr()}, NewRawEncoder(), WithChunkSize(100))
+		t.NoError(err)
+		t.NoError(c.SetStream(ctx, key, strings.NewReader(largeValue), Options{}))
+		var got string
+		t.NoError(c.Get(ctx, key, &got))
+		t.Equal(largeValue, got)
+	})
+
+	t.Run("Overwrite", func() {
+		c, mr := t.SetupRedis(WithChunkSize(100))
+		t.NoError(c.SetStream(ctx, key, strings.NewReader(largeValue), Options{}))
+		t.NoError(c.SetStream(ctx, key, strings.NewReader("blob"), Options{}))
+		t.Empty(t.chunkKeys(mr))
+	})
+
+	t.Run("Too Large", func() {
+		c, mr := t.SetupRedis(WithChunkSize(100), WithMaxValueSize(250))
+		err := c.SetStream(ctx, key, strings.NewReader(largeValue), Options{})
+		t.ErrorIs(err, ErrValueTooLarge)
+		t.Empty(mr.Keys())
+	})
+
+	t.Run("Reader Error", func() {
+		c, mr := t.SetupRedis(WithChunkSize(100))
+		r := io.MultiReader(strings.NewReader(largeValue), iotest.ErrReader(io.ErrClosedPipe))
+		err := c.SetStream(ctx, key, r, Options{})
+		t.ErrorIs(err, io.ErrClosedPipe)
+		t.False(isFailure(err))
+		t.Empty(mr.Keys())
+	})
+
+	t.Run("Invalid Options", func() {
+		c, _ := t.SetupRedis()
+		err := c.SetStream(ctx, key, strings.NewReader("blob"), Options{Expiration: time.Minute, KeepTTL: true})
+		t.Error(err)
+	})
+}
+
+func (t *CacheTestSuite) TestCache_GetStream() {
+	t.Run("Not Found", func() {
+		c, _ := t.SetupRedis()
+		t.ErrorIs(c.GetStream(ctx, key, io.Discard), ErrNotFound)
+	})
+
+	t.Run("Missing Chunk", func() {
+		c, mr := t.SetupRedis(WithChunkSize(100))
+		t.NoError(c.SetStream(ctx, key, strings.NewReader(largeValue), Options{}))
+		mr.Del(t.chunkKeys(mr)[0])
+		t.ErrorIs(c.GetStream(ctx, key, io.Discard), ErrNotFound)
+	})
+
+	t.Run("Writer Error", func() {
+		c, _ := t.SetupRedis(WithChunkSize(100))
+		t.NoError(c.SetStream(ctx, key, strings.NewReader(largeValue), Options{}))
+		err := c.GetStream(ctx, key, errWriter{})
+		t.ErrorIs(err, io.ErrShortWrite)
+		t.False(isFailure(err))
+	})
+
+	t.Run("Set Value", func() {
+		c, _ := t.SetupRedis()
+		t.NoError(c.Set(ctx, key, "value", Options{}))
+		buf := bytes.Buffer{}
+		t.NoError(c.GetStream(ctx, key, &buf))
+		t.NotEmpty(buf.Bytes())
+	})
+}
+
+// errWriter is an io.Writer that always fails.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, io.ErrShortWrite
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// WithReadTimeout sets the default timeout applied to read
+// operations, such as Get, Exists and TTL, when the context
+// passed has no deadline.
+func WithReadTimeout(d time.Duration) Option {
+	return func(c *config) {
+		if d <= 0 {
+			c.errs = append(c.errs, errors.New("redigo: read timeout must be positive"))
+			return
+		}
+		c.readTimeout = d
+	}
+}
+
+// WithWriteTimeout sets the default timeout applied to write
+// operations, such as Set, Delete and Invalidate, when
+// the context passed has no deadline.
+func WithWriteTimeout(d time.Duration) Option {
+	return func(c *config) {
+		if d <= 0 {
+			c.errs = append(c.errs, errors.New("redigo: write timeout must be positive"))
+			return
+		}
+		c.writeTimeout = d
+	}
+}
+
+// timeout applies the default read or write timeout for
+// the operation to the context if it has no deadline.
+func (c *Cache) timeout(ctx context.Context, op Operation) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	d := c.cfg.writeTimeout
+	if op.isRead() {
+		d = c.cfg.readTimeout
+	}
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// timeoutOverride applies the timeout passed via Options,
+// taking precedence over the default timeouts. The
+// deadline of the context is kept if it is sooner.
+func timeoutOverride(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"context"
+	"github.com/ainsleyclark/redigo/mocks"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+// deadlineWithin returns a matcher for a context with a
+// deadline no further away than d.
+func deadlineWithin(d time.Duration) any {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		deadline, ok := ctx.Deadline()
+		return ok && time.Until(deadline) <= d
+	})
+}
+
+func (t *CacheTestSuite) TestTimeout() {
+	c := t.Setup(func(m *mocks.RedisStore, enc *mocks.Encoder) {
+		m.On("Get", deadlineWithin(time.Second), key).
+			Return(redis.NewStringResult("", redis.Nil))
+		m.On("Del", deadlineWithin(time.Minute), key).
+			Return(redis.NewIntCmd(ctx, nil))
+		enc.On("Encode", value).
+			Return(t.GobBuf, nil)
+		m.On("Set", deadlineWithin(time.Millisecond*100), key, t.GobBuf, time.Duration(0)).
+			Return(redis.NewStatusCmd(ctx, nil))
+	})
+	c.cfg.readTimeout = time.Second
+	c.cfg.writeTimeout = time.Minute
+
+	t.ErrorIs(c.Get(ctx, key, &testCacheStruct{}), ErrNotFound)
+	t.NoError(c.Delete(ctx, key))
+	t.NoError(c.Set(ctx, key, value, Options{Timeout: time.Millisecond * 100}))
+}
+
+func TestCache_Timeout(t *testing.T) {
+	c := &Cache{cfg: config{readTimeout: time.Second, writeTimeout: time.Minute}}
+
+	t.Run("Read", func(t *testing.T) {
+		got, cancel := c.timeout(context.Background(), OpGet)
+		defer cancel()
+		deadline, ok := got.Deadline()
+		assert.True(t, ok)
+		assert.LessOrEqual(t, time.Until(deadline), time.Second)
+	})
+
+	t.Run("Write", func(t *testing.T) {
+		got, cancel := c.timeout(context.Background(), OpFlush)
+		defer cancel()
+		deadline, ok := got.Deadline()
+		assert.True(t, ok)
+		assert.Greater(t, time.Until(deadline), time.Second)
+	})
+
+	t.Run("Existing Deadline", func(t *testing.T) {
+		want, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+		got, cancel := c.timeout(want, OpGet)
+		defer cancel()
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("No Timeout", func(t *testing.T) {
+		want := context.Background()
+		got, cancel := (&Cache{}).timeout(want, OpGet)
+		defer cancel()
+		assert.Equal(t, want, got)
+	})
+}
+
+func TestTimeoutOverride(t *testing.T) {
+	want := context.Background()
+	got, cancel := timeoutOverride(want, 0)
+	cancel()
+	assert.Equal(t, want, got)
+
+	parent, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	got, cancel = timeoutOverride(parent, time.Hour)
+	defer cancel()
+	deadline, ok := got.Deadline()
+	assert.True(t, ok)
+	assert.LessOrEqual(t, time.Until(deadline), time.Millisecond)
+}
@@ -0,0 +1,133 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// tracerName is the name of the OpenTelemetry tracer
+	// used for creating spans.
+	tracerName = "github.com/ainsleyclark/redigo"
+	// spanPrefix is prepended to the name of every span.
+	spanPrefix = "redigo."
+)
+
+var (
+	// Span attribute keys.
+	attrOperation = attribute.Key("redigo.operation")
+	attrKey       = attribute.Key("redigo.key")
+	attrTags      = attribute.Key("redigo.tags")
+	attrHit       = attribute.Key("redigo.hit")
+	attrSize      = attribute.Key("redigo.size")
+	attrDBSystem  = attribute.Key("db.system").String("redis")
+)
+
+// tracingHook is a Hook that creates an OpenTelemetry
+// span for each cache operation.
+type tracingHook struct {
+	tracer   trace.Tracer
+	hashKeys bool
+}
+
+// WithTracerProvider sets the OpenTelemetry TracerProvider
+// used for creating spans. By default, the global provider
+// is used.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		if tp == nil {
+			c.errs = append(c.errs, errors.New("redigo: tracer provider cannot be nil"))
+			return
+		}
+		c.tracing.tracer = tp.Tracer(tracerName)
+	}
+}
+
+// WithHashedTraceKeys replaces keys with their SHA-256
+// hash in span attributes, for when keys contain
+// sensitive information.
+func WithHashedTraceKeys() Option {
+	return func(c *config) {
+		c.tracing.hashKeys = true
+	}
+}
+
+// newTracingHook returns a tracing hook using the global
+// TracerProvider.
+func newTracingHook() *tracingHook {
+	return &tracingHook{tracer: otel.Tracer(tracerName)}
+}
+
+// Before starts a span for the operation, the context
+// passed is used as the parent.
+func (t *tracingHook) Before(ctx context.Context, e *Event) context.Context {
+	attrs := []attribute.KeyValue{
+		attrDBSystem,
+		attrOperation.String(string(e.Operation)),
+	}
+	if e.Key != "" {
+		attrs = append(attrs, attrKey.String(t.key(e.Key)))
+	}
+	if len(e.Tags) > 0 {
+		attrs = append(attrs, attrTags.StringSlice(e.Tags))
+	}
+	ctx, _ = t.tracer.Start(ctx, spanPrefix+string(e.Operation),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx
+}
+
+// After records the outcome of the operation on the span
+// and ends it. Misses are not treated as errors.
+func (t *tracingHook) After(ctx context.Context, e *Event) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	if e.Operation == OpGet {
+		span.SetAttributes(attrHit.Bool(e.Hit))
+	}
+	if e.Size > 0 {
+		span.SetAttributes(attrSize.Int(e.Size))
+	}
+	if e.Err != nil && !errors.Is(e.Err, ErrNotFound) {
+		span.RecordError(e.Err)
+		span.SetStatus(codes.Error, e.Err.Error())
+	}
+}
+
+// start starts a child span of the current operation,
+// such as for encoding.
+func (t *tracingHook) start(ctx context.Context, name string) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, spanPrefix+name, trace.WithSpanKind(trace.SpanKindInternal))
+}
+
+// end ends the child span, recording the error and size
+// of the payload.
+func (t *tracingHook) end(span trace.Span, size int, err error) {
+	span.SetAttributes(attrSize.Int(size))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// key returns the key to be used as an attribute.
+func (t *tracingHook) key(key string) string {
+	if !t.hashKeys {
+		return key
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,125 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/ainsleyclark/redigo/mocks"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// SetupTracing is a helper to obtain a mock cache store
+// with spans recorded by the in-memory exporter.
+func (t *CacheTestSuite) SetupTracing(mf func(m *mocks.RedisStore, enc *mocks.Encoder), opts ...Option) (*Cache, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	c := t.Setup(mf)
+	cfg, err := newConfig(c.encoder, append(opts, WithTracerProvider(tp))...)
+	t.NoError(err)
+	c.cfg = cfg
+	return c, exporter
+}
+
+func (t *CacheTestSuite) TestTracing_Get() {
+	c, exporter := t.SetupTracing(func(m *mocks.RedisStore, enc *mocks.Encoder) {
+		m.On("Get", mock.Anything, key).
+			Return(redis.NewStringResult(string(t.GobBuf), nil))
+		enc.On("Decode", t.GobBuf, mock.Anything).
+			Return(nil)
+	})
+
+	err := c.Get(ctx, key, &testCacheStruct{})
+	t.NoError(err)
+
+	spans := exporter.GetSpans()
+	t.Len(spans, 2)
+
+	decode, get := spans[0], spans[1]
+	t.Equal("redigo.decode", decode.Name)
+	t.Equal("redigo.get", get.Name)
+	t.Equal(get.SpanContext.SpanID(), decode.Parent.SpanID())
+	t.Equal(codes.Unset, get.Status.Code)
+	t.Subset(get.Attributes, []attribute.KeyValue{
+		attrOperation.String("get"),
+		attrKey.String(key),
+		attrHit.Bool(true),
+		attrSize.Int(len(t.GobBuf)),
+	})
+}
+
+func (t *CacheTestSuite) TestTracing_Set() {
+	c, exporter := t.SetupTracing(func(m *mocks.RedisStore, enc *mocks.Encoder) {
+		enc.On("Encode", value).
+			Return(t.GobBuf, nil)
+		m.On("Set", mock.Anything, key, t.GobBuf, options.Expiration).
+			Return(redis.NewStatusCmd(ctx, nil))
+		m.On("SAdd", mock.Anything, tag, key).
+			Return(redis.NewIntCmd(ctx, nil))
+		m.On("Expire", mock.Anything, tag, DefaultTagExpiration).
+			Return(redis.NewBoolCmd(ctx, true))
+	}, WithHashedTraceKeys())
+
+	err := c.Set(ctx, key, value, options)
+	t.NoError(err)
+
+	spans := exporter.GetSpans()
+	t.Len(spans, 2)
+	t.Equal("redigo.encode", spans[0].Name)
+	t.Equal("redigo.set", spans[1].Name)
+
+	sum := sha256.Sum256([]byte(key))
+	t.Subset(spans[1].Attributes, []attribute.KeyValue{
+		attrKey.String(hex.EncodeToString(sum[:])),
+		attrTags.StringSlice([]string{tag}),
+	})
+}
+
+func (t *CacheTestSuite) TestTracing_Errors() {
+	tt := map[string]struct {
+		mock func(m *mocks.RedisStore, enc *mocks.Encoder)
+		call func(c *Cache)
+		want codes.Code
+	}{
+		"Miss": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				m.On("Get", mock.Anything, key).
+					Return(redis.NewStringResult("", redis.Nil))
+			},
+			func(c *Cache) {
+				_ = c.Get(ctx, key, &testCacheStruct{})
+			},
+			codes.Unset,
+		},
+		"Delete Error": {
+			func(m *mocks.RedisStore, enc *mocks.Encoder) {
+				cmd := redis.NewIntCmd(ctx, nil)
+				cmd.SetErr(errors.New("delete error"))
+				m.On("Del", mock.Anything, key).
+					Return(cmd)
+			},
+			func(c *Cache) {
+				_ = c.Delete(ctx, key)
+			},
+			codes.Error,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func() {
+			c, exporter := t.SetupTracing(test.mock)
+			test.call(c)
+			spans := exporter.GetSpans()
+			t.Len(spans, 1)
+			t.Equal(test.want, spans[0].Status.Code)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"context"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"reflect"
+	"time"
+)
+
+// UpdateFunc computes the next value of a key from its current
+// value, which is nil if the key does not exist. The value is
+// stored with the options returned, excluding Timeout. It may
+// be called more than once if the key is modified concurrently.
+type UpdateFunc func(current any) (next any, options Options, err error)
+
+// DefaultUpdateAttempts is the default number of times Update
+// attempts to apply its function if none is passed via
+// WithUpdateAttempts.
+const DefaultUpdateAttempts = 10
+
+// WithUpdateAttempts sets the maximum number of times Update
+// applies its function when the key is modified concurrently,
+// before giving up with ErrConflict.
+func WithUpdateAttempts(n int) Option {
+	return func(c *config) {
+		if n <= 0 {
+			c.errs = append(c.errs, errors.New("redigo: update attempts must be positive"))
+			return
+		}
+		c.updateAttempts = n
+	}
+}
+
+// Update performs an optimistic read-modify-write of the key.
+// The key is watched and its current value decoded into dest,
+// before being passed to fn. The value returned is written in
+// a transaction which fails if the key has been modified in
+// the meantime, in which case the update is attempted again.
+// ErrConflict is returned once the attempts set via
+// WithUpdateAttempts are exhausted.
+//
+// On success, dest holds the value stored. Errors returned
+// by fn are returned as is, leaving the key untouched. fn
+// must not call methods of the Cache.
+func (c *Cache) Update(ctx context.Context, key string, dest any, fn UpdateFunc) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.do(ctx, &Event{Operation: OpUpdate, Key: key}, func(ctx context.Context, e *Event) error {
+		k, err := c.key(key)
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < c.cfg.updateAttempts; i++ {
+			var (
+				buf     []byte
+				options Options
+				w       chunkWrite
+			)
+			err = c.client.Watch(ctx, func(tx *redis.Tx) (err error) {
+				buf, options, w, err = c.updateTx(ctx, tx, e, key, k, dest, fn)
+				return err
+			}, k)
+			if errors.Is(err, redis.TxFailedErr) {
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			err = c.writeSliding(ctx, k, c.expiration(options), e.Tags)
+			if err != nil {
+				return err
+			}
+			c.setTags(ctx, e.Tags, append([]string{k}, w.keys...)...)
+			c.deleteChunks(ctx, w.stale)
+			resetValue(dest)
+
+			return c.decode(ctx, key, buf, dest)
+		}
+
+		return ErrConflict
+	})
+}
+
+// updateTx applies fn to the current value of the prefixed
+// key k within the transaction, returning the encoded value
+// stored, the options it was stored with and its chunks.
+func (c *Cache) updateTx(ctx context.Context, tx *redis.Tx, e *Event, key, k string, dest any, fn UpdateFunc) ([]byte, Options, chunkWrite, error) {
+	var (
+		current any
+		w       chunkWrite
+	)
+	// The value of a previous attempt is cleared, as decoders
+	// merge into existing maps and structs.
+	resetValue(dest)
+	buf, err := tx.Get(ctx, k).Bytes()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, Options{}, w, err
+	}
+	if m, ok := parseManifest(buf); ok && err == nil {
+		// Chunks are never modified once written, so they
+		// are read outside of the transaction.
+		w.stale = m.keys(k)
+		buf, err = c.readChunks(ctx, k, m)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, Options{}, w, err
+		}
+	}
+	if err == nil && !isTombstone(buf) {
+		err = c.decode(ctx, key, buf, dest)
+		if err != nil {
+			return nil, Options{}, w, err
+		}
+		current = dest
+	}
+	e.Hit = current != nil
+
+	next, options, err := fn(current)
+	if err != nil {
+		return nil, Options{}, w, err
+	}
+	err = options.validate()
+	if err != nil {
+		return nil, Options{}, w, err
+	}
+
+	buf, err = c.encode(ctx, key, next)
+	if err != nil {
+		return nil, Options{}, w, err
+	}
+	e.Size = len(buf)
+	e.Tags = c.tags(options.Tags)
+
+	exp := c.expiration(options)
+	stored := buf
+	if c.cfg.chunkSize > 0 && len(buf) > c.cfg.chunkSize {
+		var ttl time.Duration
+		if options.KeepTTL {
+			ttl, err = tx.PTTL(ctx, k).Result()
+			if err != nil {
+				return nil, Options{}, w, err
+			}
+		}
+		stored, w.keys, err = c.writeChunks(ctx, k, buf, exp, ttl, options)
+		if err != nil {
+			return nil, Options{}, w, err
+		}
+	}
+
+	_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SetArgs(ctx, k, stored, setArgs(exp, options))
+		return nil
+	})
+	if err != nil {
+		c.deleteChunks(ctx, w.keys)
+		return nil, Options{}, w, err
+	}
+
+	return buf, options, w, nil
+}
+
+// resetValue sets the value dest points to to its zero value.
+func resetValue(dest any) {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+	}
+}
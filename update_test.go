@@ -0,0 +1,134 @@
+// Copyright 2020 The RediGo Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package redigo
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"time"
+)
+
+func (t *CacheTestSuite) TestCache_Update() {
+	increment := func(current any) (any, Options, error) {
+		next := testCacheStruct{Name: "counter", Value: 1}
+		if current != nil {
+			next.Value = current.(*testCacheStruct).Value + 1
+		}
+		return next, Options{Expiration: time.Minute, Tags: []string{tag}}, nil
+	}
+
+	t.Run("Create", func() {
+		c, mr := t.SetupRedis()
+		got := testCacheStruct{}
+		err := c.Update(ctx, key, &got, increment)
+		t.NoError(err)
+		t.Equal(1, got.Value)
+		t.Equal(time.Minute, mr.TTL(key))
+		t.True(mr.Exists(tag))
+	})
+
+	t.Run("Existing", func() {
+		c, _ := t.SetupRedis()
+		t.NoError(c.Set(ctx, key, testCacheStruct{Name: "counter", Value: 5}, Options{}))
+		got := testCacheStruct{}
+		err := c.Update(ctx, key, &got, increment)
+		t.NoError(err)
+		t.Equal(6, got.Value)
+	})
+
+	t.Run("Conflict Retried", func() {
+		c, mr := t.SetupRedis()
+		calls := 0
+		got := testCacheStruct{}
+		err := c.Update(ctx, key, &got, func(current any) (any, Options, error) {
+			calls++
+			if calls == 1 {
+				t.setRaw(mr, testCacheStruct{Name: "counter", Value: 10})
+			}
+			return increment(current)
+		})
+		t.NoError(err)
+		t.Equal(2, calls)
+		t.Equal(11, got.Value)
+	})
+
+	t.Run("Conflict", func() {
+		c, mr := t.SetupRedis(WithUpdateAttempts(2))
+		calls := 0
+		err := c.Update(ctx, key, &testCacheStruct{}, func(current any) (any, Options, error) {
+			calls++
+			t.setRaw(mr, testCacheStruct{Value: calls})
+			return increment(current)
+		})
+		t.ErrorIs(err, ErrConflict)
+		t.Equal(2, calls)
+	})
+
+	t.Run("Conflict Removed Fields", func() {
+		c, mr := t.SetupRedis()
+		other, err := New(&redis.Options{Addr: mr.Addr()}, NewGobEncoder())
+		t.NoError(err)
+		t.NoError(c.Set(ctx, key, map[string]int{"a": 1, "b": 2}, Options{}))
+
+		var seen []map[string]int
+		got := map[string]int{}
+		err = c.Update(ctx, key, &got, func(current any) (any, Options, error) {
+			m := *current.(*map[string]int)
+			seen = append(seen, map[string]int{"a": m["a"], "b": m["b"]})
+			if len(seen) == 1 {
+				t.NoError(other.Set(ctx, key, map[string]int{"a": 5}, Options{}))
+			}
+			return m, Options{}, nil
+		})
+		t.NoError(err)
+		t.Equal([]map[string]int{{"a": 1, "b": 2}, {"a": 5, "b": 0}}, seen)
+		t.Equal(map[string]int{"a": 5}, got)
+
+		stored := map[string]int{}
+		t.NoError(c.Get(ctx, key, &stored))
+		t.Equal(map[string]int{"a": 5}, stored)
+	})
+
+	t.Run("Func Error", func() {
+		c, mr := t.SetupRedis()
+		err := c.Update(ctx, key, &testCacheStruct{}, func(current any) (any, Options, error) {
+			return nil, Options{}, errors.New("func error")
+		})
+		t.EqualError(err, "func error")
+		t.False(mr.Exists(key))
+	})
+
+	t.Run("Invalid Options", func() {
+		c, _ := t.SetupRedis()
+		err := c.Update(ctx, key, &testCacheStruct{}, func(current any) (any, Options, error) {
+			return value, Options{Expiration: time.Minute, KeepTTL: true}, nil
+		})
+		t.Error(err)
+	})
+
+	t.Run("Decode Error", func() {
+		c, mr := t.SetupRedis()
+		t.NoError(mr.Set(key, "invalid"))
+		err := c.Update(ctx, key, &testCacheStruct{}, increment)
+		t.ErrorIs(err, ErrDecode)
+	})
+
+	t.Run("Key Too Long", func() {
+		c, _ := t.SetupRedis(WithMaxKeyLength(1))
+		err := c.Update(ctx, key, &testCacheStruct{}, increment)
+		t.ErrorIs(err, ErrKeyTooLong)
+	})
+}
+
+// setRaw stores the gob encoded value under the test key
+// directly, bypassing the cache.
+func (t *CacheTestSuite) setRaw(mr *miniredis.Miniredis, v any) {
+	buf := bytes.Buffer{}
+	t.NoError(gob.NewEncoder(&buf).Encode(v))
+	t.NoError(mr.Set(key, buf.String()))
+}